@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    toggles = {
+      source = "reinoudk/toggles"
+      version = "0.3.0"
+    }
+  }
+  required_version = "~> 1.0"
+}
+
+resource "toggles_rotary" "toggle" {
+  n = 3
+}
+
+data "toggles_rotary_preview" "next" {
+  n = toggles_rotary.toggle.n
+  active_output = toggles_rotary.toggle.active_output
+  counters = toggles_rotary.toggle.counters
+}
+
+output "active_output" {
+  value = toggles_rotary.toggle.active_output
+}
+
+output "next_active_output" {
+  value = data.toggles_rotary_preview.next.next_active_output
+}
@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    toggles = {
+      source = "reinoudk/toggles"
+      version = "0.3.0"
+    }
+  }
+  required_version = "~> 1.0"
+}
+
+locals {
+  n = 3
+}
+
+resource "toggles_random" "toggle" {
+  n = local.n
+  seed = "example"
+  exclude_active = true
+}
+
+output "active_output" {
+  value = toggles_random.toggle.active_output
+}
+
+output "outputs" {
+  value = toggles_random.toggle.outputs
+}
@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    toggles = {
+      source = "reinoudk/toggles"
+      version = "0.3.0"
+    }
+  }
+  required_version = "~> 1.0"
+}
+
+resource "toggles_schedule" "toggle" {
+  cron = "0 9 * * 1"
+  timezone = "Europe/Amsterdam"
+}
+
+output "active_output" {
+  value = toggles_schedule.toggle.active_output
+}
+
+output "next_toggle_timestamp" {
+  value = toggles_schedule.toggle.next_toggle_timestamp
+}
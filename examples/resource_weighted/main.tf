@@ -0,0 +1,34 @@
+terraform {
+  required_providers {
+    toggles = {
+      source = "reinoudk/toggles"
+      version = "0.3.0"
+    }
+  }
+  required_version = "~> 1.0"
+}
+
+locals {
+  weights = [3, 1]
+}
+
+resource "toggles_weighted" "toggle" {
+  weights = local.weights
+}
+
+resource "random_string" "rand" {
+  length = 10
+  count = length(local.weights)
+
+  keepers = {
+    rotate = toggles_weighted.toggle.counters[count.index]
+  }
+}
+
+output "active_rand" {
+  value = random_string.rand[toggles_weighted.toggle.active_output]
+}
+
+output "outputs" {
+  value = toggles_weighted.toggle.outputs
+}
@@ -0,0 +1,15 @@
+package toggles
+
+import (
+	"time"
+)
+
+// clock returns the current time as seen by the toggles.
+type clock func() time.Time
+
+// fixedClock returns a clock that is frozen at the given time.
+func fixedClock(t time.Time) clock {
+	return func() time.Time {
+		return t
+	}
+}
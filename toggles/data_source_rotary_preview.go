@@ -0,0 +1,132 @@
+package toggles
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strconv"
+)
+
+func dataSourceRotaryPreview() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRotaryPreviewRead,
+		Schema: map[string]*schema.Schema {
+			"n": {
+				Type: schema.TypeInt,
+				Description: "The number of outputs after the rotation. Should be between 2 and 256.",
+				Required: true,
+				ValidateFunc: validation.IntBetween(2, 256),
+			},
+			"active_output": {
+				Type: schema.TypeInt,
+				Description: "The 0-index based number of the currently active output.",
+				Required: true,
+				ValidateFunc: validation.IntBetween(0, 255),
+			},
+			"counters": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				Description: "The current counters. Defaults to the counters of a new rotary with n outputs.",
+				Optional: true,
+				MaxItems: 256,
+			},
+			"step": {
+				Type: schema.TypeInt,
+				Description: "The number of outputs to advance on the rotation. Negative values rotate backwards. Defaults to 1. Should not be a multiple of n.",
+				Optional: true,
+				Default: 1,
+			},
+			"disabled_outputs": {
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+					ValidateFunc: validation.IntBetween(0, 255),
+				},
+				Description: "A set of 0-index based numbers of outputs that are skipped when rotating.",
+				Optional: true,
+			},
+			"next_outputs": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeBool,
+				},
+				Description: "A list of n boolean outputs after the rotation.",
+				Computed: true,
+			},
+			"next_active_output": {
+				Type: schema.TypeInt,
+				Description: "The 0-index based number of the active output after the rotation.",
+				Computed: true,
+			},
+			"next_counters": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+				Description: "A list of counters after the rotation.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+// readRotaryPreviewState reads the current state of the rotary to preview. Without counters, the state is that of a new
+// rotary with n outputs.
+func readRotaryPreviewState(d *schema.ResourceData) (rotaryState, error) {
+	activeOutput := d.Get("active_output").(int)
+	counters := d.Get("counters").([]interface{})
+
+	if len(counters) == 0 {
+		n := d.Get("n").(int)
+		if activeOutput >= n {
+			return rotaryState{}, fmt.Errorf("active_output (%d) should be between 0 and %d", activeOutput, n-1)
+		}
+
+		return newRotaryState(n, activeOutput), nil
+	}
+
+	if activeOutput >= len(counters) {
+		return rotaryState{}, fmt.Errorf("active_output (%d) should be between 0 and %d", activeOutput, len(counters)-1)
+	}
+
+	return toRotaryState(activeOutput, counters), nil
+}
+
+// dataSourceRotaryPreviewRead computes the state of a rotary after its next rotation, using the same rotation as the
+// rotary resource. When the number of counters differs from n, the rotary is resized like the rotary resource does
+// when n changes.
+func dataSourceRotaryPreviewRead(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c, err := newRotaryConfig(d.Get("n").(int), d.Get("step").(int), d.Get("disabled_outputs").(*schema.Set).List())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	state, err := readRotaryPreviewState(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	next, err := state.next(c, true)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	setNext := func(key string, value interface{}) error {
+		return d.Set("next_"+key, value)
+	}
+
+	if err := setRotaryState(setNext, next); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(strconv.Itoa(schema.HashString(fmt.Sprintf("%d-%v", next.ActiveOutput, next.Counters))))
+
+	return diags
+}
@@ -0,0 +1,141 @@
+package toggles
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAccRotaryPreview(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Previewing without counters should rotate a new rotary.
+				Config: testAccRotaryPreviewDataSource(3, 0, "null", 1, "[]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_active_output", "1"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_outputs.#", "3"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_outputs.0", "false"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_outputs.1", "true"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_outputs.2", "false"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_counters.0", "1"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_counters.1", "1"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_counters.2", "0"),
+				),
+			},
+			{
+				// Previewing with counters should wrap around and increment the counter of the next active output.
+				Config: testAccRotaryPreviewDataSource(3, 2, "[2, 1, 1]", 1, "[]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_active_output", "0"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_counters.0", "3"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_counters.1", "1"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_counters.2", "1"),
+				),
+			},
+			{
+				// Previewing with a negative step and disabled outputs should rotate backwards and skip them.
+				Config: testAccRotaryPreviewDataSource(4, 0, "[1, 1, 1, 1]", -1, "[3, 2]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_active_output", "1"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_counters.1", "2"),
+				),
+			},
+			{
+				// Previewing with fewer counters than n should grow the rotary before rotating.
+				Config: testAccRotaryPreviewDataSource(3, 1, "[1, 1]", 1, "[]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_active_output", "2"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_counters.#", "3"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_counters.2", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRotaryPreview_rotary(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The preview of a rotary should match the state of the rotary after it rotates.
+				PreConfig: testAccTick,
+				Config: testAccRotaryPreviewDataSourceOfRotary("initial"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_active_output", "2"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_counters.2", "1"),
+				),
+			},
+			{
+				PreConfig: testAccTick,
+				Config: testAccRotaryPreviewDataSourceOfRotary("change-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_active_output", "0"),
+					resource.TestCheckResourceAttr("data.toggles_rotary_preview.test", "next_counters.0", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRotaryPreview_invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// An active output without a counter should be rejected.
+				Config: testAccRotaryPreviewDataSource(3, 2, "[1, 1]", 1, "[]"),
+				ExpectError: regexp.MustCompile(`active_output \(2\) should be between 0 and 1`),
+			},
+			{
+				// A step that is a multiple of n should be rejected.
+				Config: testAccRotaryPreviewDataSource(3, 0, "null", 3, "[]"),
+				ExpectError: regexp.MustCompile(`step \(3\) should not be a multiple of n \(3\)`),
+			},
+			{
+				// Disabling all outputs should be rejected.
+				Config: testAccRotaryPreviewDataSource(2, 0, "null", 1, "[0, 1]"),
+				ExpectError: regexp.MustCompile("all 2 outputs are disabled"),
+			},
+		},
+	})
+}
+
+func testAccRotaryPreviewDataSource (n int, activeOutput int, counters string, step int, disabledOutputs string) string {
+	return fmt.Sprintf(`
+data "toggles_rotary_preview" "test" {
+  n = %d
+  active_output = %d
+  counters = %s
+  step = %d
+  disabled_outputs = %s
+}
+`, n, activeOutput, counters, step, disabledOutputs)
+}
+
+func testAccRotaryPreviewDataSourceOfRotary (trigger string) string {
+	return fmt.Sprintf(`
+resource "toggles_rotary" "test" {
+  trigger = "%s"
+  n = 4
+  step = 2
+  disabled_outputs = [1]
+}
+
+data "toggles_rotary_preview" "test" {
+  n = toggles_rotary.test.n
+  active_output = toggles_rotary.test.active_output
+  counters = toggles_rotary.test.counters
+  step = toggles_rotary.test.step
+  disabled_outputs = toggles_rotary.test.disabled_outputs
+}
+`, trigger)
+}
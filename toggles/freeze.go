@@ -0,0 +1,91 @@
+package toggles
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"log"
+	"strings"
+)
+
+const (
+	// freezeBehaviorWarn plans toggles that a frozen provider suppressed without toggling, and warns about them.
+	freezeBehaviorWarn = "warn"
+	// freezeBehaviorError fails plans of toggles that a frozen provider suppressed.
+	freezeBehaviorError = "error"
+)
+
+// freezeBehaviors are the valid values of the freeze_behavior attribute.
+var freezeBehaviors = []string{freezeBehaviorWarn, freezeBehaviorError}
+
+// thawed returns a copy of the provider configuration that is not frozen, to determine which toggles a frozen provider
+// suppresses.
+func (meta *providerMeta) thawed() *providerMeta {
+	thawed := *meta
+	thawed.Frozen = false
+
+	return &thawed
+}
+
+// describeTrigger describes the change of the trigger or triggers that toggles a toggle in the given trigger mode.
+func describeTrigger(d resourceGetter, mode string) string {
+	if mode == triggerModeAlways {
+		return "the always trigger mode"
+	}
+
+	config := d.GetRawConfig()
+	if !config.IsNull() && (!config.GetAttr("trigger").IsKnown() || !config.GetAttr("triggers").IsWhollyKnown()) {
+		return "a change of the trigger or triggers, which are only known after apply"
+	}
+
+	var changes []string
+	if d.HasChange("trigger") {
+		old, new := d.GetChange("trigger")
+		changes = append(changes, fmt.Sprintf("a change of the trigger from %q to %q", old, new))
+	}
+
+	if d.HasChange("triggers") {
+		keys := make([]string, 0)
+		for _, key := range changedTriggerKeys(d) {
+			keys = append(keys, fmt.Sprintf("%q", key))
+		}
+
+		changes = append(changes, fmt.Sprintf("a change of the triggers %s", strings.Join(keys, ", ")))
+	}
+
+	return strings.Join(changes, " and ")
+}
+
+// suppressedToggleMessage returns the message that a frozen provider did not toggle the resource because of change.
+func suppressedToggleMessage(resourceType string, id string, change string) string {
+	return fmt.Sprintf("the provider is frozen, so %s %s was not toggled by %s", resourceType, id, change)
+}
+
+// reportSuppressedToggle reports a toggle that a frozen provider suppressed during plan, according to the freeze
+// behavior. CustomizeDiff cannot return warnings, so with the warn freeze behavior the message is logged as a warning,
+// and the update of the resource reports it again as a warning diagnostic.
+func reportSuppressedToggle(meta *providerMeta, message string) error {
+	if meta.FreezeBehavior == freezeBehaviorError {
+		return errors.New(message)
+	}
+
+	log.Printf("[WARN] %s", message)
+
+	return nil
+}
+
+// suppressedToggleDiagnostics returns a warning or error diagnostic, according to the freeze behavior, of a toggle that
+// a frozen provider suppressed, which is reported during apply.
+func suppressedToggleDiagnostics(meta *providerMeta, message string) diag.Diagnostics {
+	severity := diag.Warning
+	if meta.FreezeBehavior == freezeBehaviorError {
+		severity = diag.Error
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity: severity,
+			Summary:  message,
+		},
+	}
+}
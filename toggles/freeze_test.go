@@ -0,0 +1,94 @@
+package toggles
+
+import (
+	"context"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"strings"
+	"testing"
+)
+
+// testRawValue returns an object of the type of the resource with the given attributes, and all other attributes null.
+func testRawValue (r *schema.Resource, values map[string]cty.Value) cty.Value {
+	attributes := map[string]cty.Value{}
+	for name, ty := range r.CoreConfigSchema().ImpliedType().AttributeTypes() {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		} else {
+			attributes[name] = cty.NullVal(ty)
+		}
+	}
+
+	return cty.ObjectVal(attributes)
+}
+
+// testApplyFrozenTriggerChange applies a change of the trigger from initial to change-1 with a frozen provider, and with
+// a plan in which all attributes are known, and returns the diagnostics of the apply.
+func testApplyFrozenTriggerChange (r *schema.Resource, attributes map[string]string) diag.Diagnostics {
+	meta := newProviderMeta()
+	meta.Frozen = true
+
+	attributes["trigger"] = "initial"
+	state := &terraform.InstanceState{ID: "test", Attributes: attributes}
+
+	config := testRawValue(r, map[string]cty.Value{"trigger": cty.StringVal("change-1")})
+	diff := &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"trigger": {Old: "initial", New: "change-1"},
+		},
+		RawConfig: config,
+		RawPlan:   config,
+	}
+
+	_, diags := r.Apply(context.Background(), state, diff, meta)
+
+	return diags
+}
+
+func TestSuppressedToggleDiagnostics_leapfrogUpdate(t *testing.T) {
+	diags := testApplyFrozenTriggerChange(resourceLeapfrog(), map[string]string{
+		"alpha": "true",
+		"beta": "false",
+		"alpha_timestamp": "2021-01-01T00:00:00Z",
+		"beta_timestamp": "2021-01-01T00:00:00Z",
+		"timestamp_format": timestampFormatRFC3339,
+	})
+
+	testCheckSuppressedToggleWarning(t, diags, `toggles_leapfrog test was not toggled by a change of the trigger from "initial" to "change-1"`)
+}
+
+func TestSuppressedToggleDiagnostics_rotaryUpdate(t *testing.T) {
+	diags := testApplyFrozenTriggerChange(resourceRotary(), map[string]string{
+		"n": "2",
+		"step": "1",
+		"active_output": "0",
+		"outputs.#": "2",
+		"outputs.0": "true",
+		"outputs.1": "false",
+		"counters.#": "2",
+		"counters.0": "1",
+		"counters.1": "0",
+		"timestamps.#": "2",
+		"timestamps.0": "2021-01-01T00:00:00Z",
+		"timestamps.1": "2021-01-01T00:00:00Z",
+		"timestamp_format": timestampFormatRFC3339,
+	})
+
+	testCheckSuppressedToggleWarning(t, diags, `toggles_rotary test was not toggled by a change of the trigger from "initial" to "change-1"`)
+}
+
+func testCheckSuppressedToggleWarning (t *testing.T, diags diag.Diagnostics, expected string) {
+	if len(diags) != 1 {
+		t.Fatalf("expected a single diagnostic, got %#v", diags)
+	}
+
+	if diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a warning, got %#v", diags[0])
+	}
+
+	if !strings.Contains(diags[0].Summary, expected) {
+		t.Fatalf("expected the warning to contain %q, got %q", expected, diags[0].Summary)
+	}
+}
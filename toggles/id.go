@@ -0,0 +1,40 @@
+package toggles
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-uuid"
+)
+
+// legacyToggleID is the constant ID that version 0 of the leapfrog and rotary resources used for every toggle.
+const legacyToggleID = "toggle"
+
+// newToggleID returns a new unique ID (UUID) for a toggle.
+func newToggleID() (string, error) {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return "", fmt.Errorf("could not generate id: %+v", err)
+	}
+
+	return id, nil
+}
+
+// migratedToggleID returns the ID, formatted as a UUID, of a toggle that is migrated from the legacy ID. It is a hash
+// of the prior state, as the state is upgraded again by every plan until it is stored, and each upgrade must agree on
+// the ID.
+func migratedToggleID(rawState map[string]interface{}) (string, error) {
+	encoded, err := json.Marshal(rawState)
+	if err != nil {
+		return "", fmt.Errorf("could not encode the state to derive the id: %+v", err)
+	}
+
+	sum := sha256.Sum256(encoded)
+
+	id, err := uuid.FormatUUID(sum[:16])
+	if err != nil {
+		return "", fmt.Errorf("could not format id: %+v", err)
+	}
+
+	return id, nil
+}
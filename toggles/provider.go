@@ -1,15 +1,142 @@
 package toggles
 
 import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"time"
 )
 
 func Provider() *schema.Provider {
 	return &schema.Provider{
-		Schema: map[string]*schema.Schema{},
+		Schema: map[string]*schema.Schema{
+			"default_trigger_mode": {
+				Type: schema.TypeString,
+				Description: "The trigger mode of all toggles that do not set a trigger_mode: on_change, always or never.",
+				Optional: true,
+				ValidateFunc: validation.StringInSlice(triggerModes, false),
+			},
+			"timezone": {
+				Type: schema.TypeString,
+				Description: "The IANA time zone of the timestamps, and the default time zone of new schedules. Defaults to UTC.",
+				Optional: true,
+				ValidateFunc: validateTimezone,
+			},
+			"timestamp_format": {
+				Type: schema.TypeString,
+				Description: "The timestamp format of new toggles that do not set a timestamp_format: rfc3339, rfc3339nano, unix or unix_ms. Defaults to rfc3339nano.",
+				Optional: true,
+				ValidateFunc: validation.StringInSlice(timestampFormats, false),
+			},
+			"frozen": {
+				Type: schema.TypeBool,
+				Description: "A kill switch that prevents all toggles from toggling, e.g. during a change freeze. Can also be set with the TOGGLES_FREEZE environment variable. Defaults to false.",
+				Optional: true,
+				DefaultFunc: schema.EnvDefaultFunc("TOGGLES_FREEZE", false),
+			},
+			"freeze_behavior": {
+				Type: schema.TypeString,
+				Description: "How leapfrogs and rotaries report a toggle that was suppressed because the provider is frozen: warn or error. Defaults to warn.",
+				Optional: true,
+				Default: freezeBehaviorWarn,
+				ValidateFunc: validation.StringInSlice(freezeBehaviors, false),
+			},
+			"fixed_time": {
+				Type: schema.TypeString,
+				Description: "An RFC3339 timestamp at which the clock of the toggles is frozen, e.g. for deterministic runs in CI. Can also be set with the TOGGLES_FIXED_TIME environment variable. Defaults to the current time.",
+				Optional: true,
+				DefaultFunc: schema.EnvDefaultFunc("TOGGLES_FIXED_TIME", nil),
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"toggles_rotary_preview": dataSourceRotaryPreview(),
+		},
 		ResourcesMap: map[string]*schema.Resource{
 			"toggles_leapfrog": resourceLeapfrog(),
+			"toggles_random": resourceRandom(),
 			"toggles_rotary": resourceRotary(),
+			"toggles_schedule": resourceSchedule(),
+			"toggles_weighted": resourceWeighted(),
 		},
+		ConfigureContextFunc: providerConfigure,
+	}
+}
+
+// providerMeta holds the configuration of the provider that is shared by all toggles. It is passed as meta to the CRUD
+// and CustomizeDiff functions.
+type providerMeta struct {
+	// Clock returns the current time.
+	Clock clock
+	// DefaultTriggerMode is the trigger mode of toggles that do not set a trigger mode, unless it is empty.
+	DefaultTriggerMode string
+	// Location is the time zone of the timestamps, and the default time zone of new schedules.
+	Location *time.Location
+	// TimestampFormat is the timestamp format of new toggles that do not set a timestamp format.
+	TimestampFormat string
+	// Frozen prevents all toggles from toggling.
+	Frozen bool
+	// FreezeBehavior is how leapfrogs and rotaries report a toggle that was suppressed because the provider is frozen.
+	FreezeBehavior string
+}
+
+// newProviderMeta returns the configuration of a provider without any arguments.
+func newProviderMeta() *providerMeta {
+	return &providerMeta{
+		Clock:           time.Now,
+		Location:        time.UTC,
+		TimestampFormat: timestampFormatRFC3339Nano,
+		FreezeBehavior:  freezeBehaviorWarn,
 	}
 }
+
+// getProviderMeta returns the configuration of the provider from the meta passed to the CRUD and CustomizeDiff
+// functions. It falls back to the configuration of a provider without any arguments when the provider was not
+// configured.
+func getProviderMeta(m interface{}) *providerMeta {
+	if meta, ok := m.(*providerMeta); ok && meta != nil {
+		return meta
+	}
+
+	return newProviderMeta()
+}
+
+// now returns the current time in the time zone of the provider.
+func (meta *providerMeta) now() time.Time {
+	return meta.Clock().In(meta.Location)
+}
+
+// providerConfigure returns the providerMeta that is passed as meta to the CRUD and CustomizeDiff functions of the
+// toggles.
+func providerConfigure(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	meta := newProviderMeta()
+
+	if fixedTime := d.Get("fixed_time").(string); fixedTime != "" {
+		t, err := time.Parse(time.RFC3339, fixedTime)
+		if err != nil {
+			return nil, diag.Errorf("could not parse fixed_time: %+v", err)
+		}
+
+		meta.Clock = fixedClock(t)
+	}
+
+	if timezone := d.Get("timezone").(string); timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, diag.Errorf("could not load timezone: %+v", err)
+		}
+
+		meta.Location = location
+	}
+
+	if timestampFormat := d.Get("timestamp_format").(string); timestampFormat != "" {
+		meta.TimestampFormat = timestampFormat
+	}
+
+	meta.DefaultTriggerMode = d.Get("default_trigger_mode").(string)
+	meta.Frozen = d.Get("frozen").(bool)
+	meta.FreezeBehavior = d.Get("freeze_behavior").(string)
+
+	return meta, nil
+}
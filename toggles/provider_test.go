@@ -1,7 +1,11 @@
 package toggles
 
 import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"os"
+	"regexp"
 	"testing"
 )
 
@@ -16,4 +20,349 @@ func TestProvider(t *testing.T) {
 		t.Fatalf("err: %s", err)
 	}
 
-}
\ No newline at end of file
+}
+
+func TestAccProvider_fixedTime(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Configuring the provider with a fixed time should use it for the timestamps of the toggles.
+				Config: `
+provider "toggles" {
+  fixed_time = "2022-02-02T02:02:02Z"
+}
+
+resource "toggles_leapfrog" "test" {
+  trigger = "initial"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2022-02-02T02:02:02Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp", "2022-02-02T02:02:02Z"),
+				),
+			},
+			{
+				// Configuring the provider with an invalid fixed time should fail.
+				Config: `
+provider "toggles" {
+  fixed_time = "yesterday"
+}
+
+resource "toggles_leapfrog" "test" {
+  trigger = "initial"
+}
+`,
+				ExpectError: regexp.MustCompile("expected \"fixed_time\" to be a valid RFC3339 date"),
+			},
+			{
+				// Fixing the time with the environment variable should use it for the timestamps of the toggles.
+				PreConfig: testAccFixTime("2022-02-02T03:03:03Z"),
+				Config: `
+resource "toggles_leapfrog" "test" {
+  trigger = "change-1"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2022-02-02T02:02:02Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp", "2022-02-02T03:03:03Z"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProvider_defaultTriggerMode(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: testAccTick,
+				Config: testAccProviderDefaultTriggerModeConfig("initial", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+				),
+			},
+			{
+				// Changing the trigger should not toggle with the never trigger mode of the provider.
+				PreConfig: testAccTick,
+				Config: testAccProviderDefaultTriggerModeConfig("change-1", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+				),
+			},
+			{
+				// The trigger mode of the toggle should override the trigger mode of the provider.
+				PreConfig: testAccTick,
+				Config: testAccProviderDefaultTriggerModeConfig("change-2", `"on_change"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProvider_frozen(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: testAccFixTime("2021-01-01T10:30:00Z"),
+				Config: testAccProviderFrozenConfig(false, "initial"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2021-01-01T11:00:00Z"),
+				),
+			},
+			{
+				// Neither a changed trigger nor a passed scheduled toggle should toggle while the provider is frozen.
+				PreConfig: testAccFixTime("2021-01-01T11:00:00Z"),
+				Config: testAccProviderFrozenConfig(true, "change-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2021-01-01T11:00:00Z"),
+				),
+			},
+			{
+				// Lifting the freeze should toggle the schedule, but not replay the trigger change made during the freeze.
+				PreConfig: testAccFixTime("2021-01-01T11:15:00Z"),
+				Config: testAccProviderFrozenConfig(false, "change-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "last_toggle_timestamp", "2021-01-01T11:00:00Z"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2021-01-01T12:00:00Z"),
+				),
+			},
+			{
+				PreConfig: testAccFixTime("2021-01-01T11:30:00Z"),
+				Config: testAccProviderFrozenConfig(false, "change-2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProvider_timestamps(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// New toggles should use the time zone and the timestamp format of the provider by default.
+				PreConfig: testAccFixTime("2022-02-02T02:02:02Z"),
+				Config: `
+provider "toggles" {
+  timezone = "Europe/Amsterdam"
+  timestamp_format = "rfc3339"
+}
+
+resource "toggles_leapfrog" "test" {
+  trigger = "initial"
+}
+
+resource "toggles_rotary" "test" {
+  trigger = "initial"
+  timestamp_format = "unix"
+}
+
+resource "toggles_schedule" "test" {
+  cron = "0 9 * * *"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "timestamp_format", "rfc3339"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2022-02-02T03:02:02+01:00"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamp_format", "unix"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamps.0", "1643767322"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "timezone", "Europe/Amsterdam"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2022-02-02T09:00:00+01:00"),
+				),
+			},
+			{
+				// Changing the defaults of the provider should not change existing toggles.
+				PreConfig: testAccFixTime("2022-02-02T03:03:03Z"),
+				Config: `
+resource "toggles_leapfrog" "test" {
+  trigger = "initial"
+}
+
+resource "toggles_rotary" "test" {
+  trigger = "initial"
+  timestamp_format = "unix"
+}
+
+resource "toggles_schedule" "test" {
+  cron = "0 9 * * *"
+}
+`,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccProviderDefaultTriggerModeConfig (trigger string, triggerMode string) string {
+	return fmt.Sprintf(`
+provider "toggles" {
+  default_trigger_mode = "never"
+}
+
+resource "toggles_leapfrog" "test" {
+  trigger = "%s"
+  trigger_mode = %s
+}
+`, trigger, triggerMode)
+}
+
+func testAccProviderFrozenConfig (frozen bool, trigger string) string {
+	return fmt.Sprintf(`
+provider "toggles" {
+  frozen = %t
+}
+
+resource "toggles_rotary" "test" {
+  trigger = "%s"
+}
+
+resource "toggles_schedule" "test" {
+  cron = "0 * * * *"
+}
+`, frozen, trigger)
+}
+
+func TestAccProvider_freezeBehavior(t *testing.T) {
+	defer testAccRestoreTime()
+	defer os.Unsetenv("TOGGLES_FREEZE")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: testAccTick,
+				Config: testAccProviderFreezeBehaviorConfig("", "initial", "null"),
+			},
+			{
+				// A suppressed toggle of a leapfrog should fail the plan with the error freeze behavior.
+				PreConfig: func() {
+					testAccTick()
+					os.Setenv("TOGGLES_FREEZE", "true")
+				},
+				Config: testAccProviderFreezeBehaviorConfig(`freeze_behavior = "error"`, "change-1", "null"),
+				ExpectError: regexp.MustCompile(`toggles_leapfrog [-0-9a-f]+ was not toggled by a change of the trigger from "initial" to\s+"change-1"`),
+			},
+			{
+				// A suppressed rotation of a rotary should fail the plan with the error freeze behavior.
+				PreConfig: testAccTick,
+				Config: testAccProviderFreezeBehaviorConfig(`freeze_behavior = "error"`, "initial", "1"),
+				ExpectError: regexp.MustCompile(`toggles_rotary [-0-9a-f]+ was not toggled by pinned_output 1`),
+			},
+			{
+				// A suppressed toggle should only warn with the warn freeze behavior.
+				PreConfig: testAccTick,
+				Config: testAccProviderFreezeBehaviorConfig(`freeze_behavior = "warn"`, "change-1", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+				),
+			},
+			{
+				// Unfreezing the provider should toggle on the next trigger change.
+				PreConfig: func() {
+					testAccTick()
+					os.Unsetenv("TOGGLES_FREEZE")
+				},
+				Config: testAccProviderFreezeBehaviorConfig(`freeze_behavior = "error"`, "change-2", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccProviderFreezeBehaviorConfig (freezeBehavior string, trigger string, pinnedOutput string) string {
+	return fmt.Sprintf(`
+provider "toggles" {
+  %s
+}
+
+resource "toggles_leapfrog" "test" {
+  trigger = "%s"
+}
+
+resource "toggles_rotary" "test" {
+  trigger = "%[2]s"
+  pinned_output = %s
+}
+`, freezeBehavior, trigger, pinnedOutput)
+}
+
+func TestAccProvider_frozenDisabledOutput(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: testAccTick,
+				Config: testAccProviderFrozenDisabledOutputConfig(false, "error", "[]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+				),
+			},
+			{
+				// Disabling the active output should fail the plan with the error freeze behavior.
+				PreConfig: testAccTick,
+				Config: testAccProviderFrozenDisabledOutputConfig(true, "error", "[0]"),
+				ExpectError: regexp.MustCompile(`toggles_rotary [-0-9a-f]+ was not toggled by disabling the active output 0`),
+			},
+			{
+				// Disabling the active output should hold it active with the warn freeze behavior.
+				PreConfig: testAccTick,
+				Config: testAccProviderFrozenDisabledOutputConfig(true, "warn", "[0]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+				),
+			},
+			{
+				// Unfreezing the provider should rotate off the disabled output.
+				PreConfig: testAccTick,
+				Config: testAccProviderFrozenDisabledOutputConfig(false, "warn", "[0]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccProviderFrozenDisabledOutputConfig (frozen bool, freezeBehavior string, disabledOutputs string) string {
+	return fmt.Sprintf(`
+provider "toggles" {
+  frozen = %t
+  freeze_behavior = "%s"
+}
+
+resource "toggles_rotary" "test" {
+  trigger = "initial"
+  disabled_outputs = %s
+}
+`, frozen, freezeBehavior, disabledOutputs)
+}
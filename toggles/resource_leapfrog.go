@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strings"
 	"time"
 )
 
@@ -15,20 +17,121 @@ func resourceLeapfrog() *schema.Resource {
 		UpdateContext: resourceLeapfrogUpdate,
 		DeleteContext: resourceLeapfrogDelete,
 		CustomizeDiff: customizeDiffLeapfrog,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLeapfrogImport,
+		},
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceLeapfrogV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceLeapfrogStateUpgradeV0,
+				Version: 0,
+			},
+			{
+				Type:    resourceLeapfrogV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceLeapfrogStateUpgradeV1,
+				Version: 1,
+			},
+		},
 		Schema: map[string]*schema.Schema {
 			"trigger": {
 				Type: schema.TypeString,
 				Description: "An arbitrary string value that, when changed, toggles the output.",
 				Optional: true,
 			},
+			"triggers": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A map of arbitrary string values that, when any of them changes, toggles the output.",
+				Optional: true,
+			},
+			"trigger_mode": {
+				Type: schema.TypeString,
+				Description: "Determines when the output is toggled: on_change of the trigger or triggers, always or never. When not set, the output is toggled on each apply if both the trigger and triggers are empty, and on changes otherwise.",
+				Optional: true,
+				ValidateFunc: validation.StringInSlice(triggerModes, false),
+			},
+			"force_active": {
+				Type: schema.TypeString,
+				Description: "Forces the active output to either alpha or beta, regardless of the triggers.",
+				Optional: true,
+				ValidateFunc: validation.StringInSlice([]string{"alpha", "beta"}, false),
+			},
+			"rotation_interval": {
+				Type: schema.TypeString,
+				Description: "A duration, e.g. 720h, after which the output is toggled when the active side was not updated since.",
+				Optional: true,
+				ValidateFunc: validateRotationInterval,
+			},
+			"last_toggle_reason": {
+				Type: schema.TypeString,
+				Description: "The reason of the last toggle: trigger, rotation_interval or force_active.",
+				Computed: true,
+			},
+			"last_trigger_keys": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The sorted keys of the triggers that were added, removed or changed on the last toggle.",
+				Computed: true,
+			},
+			"alpha_value": {
+				Type: schema.TypeString,
+				Description: "An arbitrary string value that represents the alpha output, e.g. blue.",
+				Optional: true,
+				// Computed, so it can be set again after SetNew of alpha or beta cleared its diff.
+				Computed: true,
+			},
+			"beta_value": {
+				Type: schema.TypeString,
+				Description: "An arbitrary string value that represents the beta output, e.g. green.",
+				Optional: true,
+				// Computed, so it can be set again after SetNew of alpha or beta cleared its diff.
+				Computed: true,
+			},
+			"active_side": {
+				Type: schema.TypeString,
+				Description: "The active output, either alpha or beta.",
+				Computed: true,
+			},
+			"active_value": {
+				Type: schema.TypeString,
+				Description: "The value of the active output, either alpha_value or beta_value.",
+				Computed: true,
+			},
+			"inactive_value": {
+				Type: schema.TypeString,
+				Description: "The value of the inactive output, either alpha_value or beta_value.",
+				Computed: true,
+			},
+			"timestamp_format": {
+				Type: schema.TypeString,
+				Description: "The format of the timestamps: rfc3339, rfc3339nano, unix or unix_ms. Defaults to rfc3339nano.",
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice(timestampFormats, false),
+			},
 			"alpha_timestamp": {
 				Type: schema.TypeString,
-				Description: "An UTC RFC333 timestamp denoting the last time the alpha value was updated.",
+				Description: "A timestamp in the timestamp_format denoting the last time the alpha value was updated.",
 				Computed: true,
 			},
 			"beta_timestamp": {
 				Type: schema.TypeString,
-				Description: "An UTC RFC333 timestamp denoting the last time the beta value was updated.",
+				Description: "A timestamp in the timestamp_format denoting the last time the beta value was updated.",
+				Computed: true,
+			},
+			"alpha_timestamp_unix": {
+				Type: schema.TypeInt,
+				Description: "The alpha_timestamp as the number of seconds since the unix epoch.",
+				Computed: true,
+			},
+			"beta_timestamp_unix": {
+				Type: schema.TypeInt,
+				Description: "The beta_timestamp as the number of seconds since the unix epoch.",
 				Computed: true,
 			},
 			"alpha": {
@@ -45,29 +148,334 @@ func resourceLeapfrog() *schema.Resource {
 	}
 }
 
+const (
+	// leapfrogReasonTrigger denotes a toggle caused by the trigger, the triggers or the trigger mode.
+	leapfrogReasonTrigger = "trigger"
+	// leapfrogReasonRotationInterval denotes a toggle caused by the rotation interval elapsing.
+	leapfrogReasonRotationInterval = "rotation_interval"
+	// leapfrogReasonForceActive denotes a toggle to the forced active side.
+	leapfrogReasonForceActive = "force_active"
+)
+
+// validateRotationInterval validates that the value is a positive duration.
+func validateRotationInterval(value interface{}, key string) ([]string, []error) {
+	interval, err := time.ParseDuration(value.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("expected %s to be a valid duration, got %s: %+v", key, value, err)}
+	}
+
+	if interval <= 0 {
+		return nil, []error{fmt.Errorf("expected %s to be a positive duration, got %s", key, value)}
+	}
+
+	return nil, nil
+}
+
+// setLeapfrogValues sets the active side and the active and inactive values using set, which is either SetNew of
+// schema.ResourceDiff or Set of schema.ResourceData.
+func setLeapfrogValues(set func(string, interface{}) error, d resourceGetter, alpha bool) error {
+	activeSide, activeValue, inactiveValue := "alpha", d.Get("alpha_value"), d.Get("beta_value")
+	if !alpha {
+		activeSide, activeValue, inactiveValue = "beta", d.Get("beta_value"), d.Get("alpha_value")
+	}
+
+	if err := set("active_side", activeSide); err != nil {
+		return fmt.Errorf("could not set active_side: %+v", err)
+	}
+
+	if err := set("active_value", activeValue); err != nil {
+		return fmt.Errorf("could not set active_value: %+v", err)
+	}
+
+	if err := set("inactive_value", inactiveValue); err != nil {
+		return fmt.Errorf("could not set inactive_value: %+v", err)
+	}
+
+	return nil
+}
+
+// setNewLeapfrogLabels sets alpha_value and beta_value from the configuration, and returns whether both are known.
+// SetNew of alpha or beta clears the diff of every attribute that starts with their name, including these labels.
+func setNewLeapfrogLabels(d *schema.ResourceDiff) (bool, error) {
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		return d.NewValueKnown("alpha_value") && d.NewValueKnown("beta_value"), nil
+	}
+
+	known := true
+	for _, key := range []string{"alpha_value", "beta_value"} {
+		value := config.GetAttr(key)
+
+		var err error
+		switch {
+		case !value.IsKnown():
+			known = false
+			err = d.SetNewComputed(key)
+		case value.IsNull():
+			// A label removed from the configuration keeps its last value.
+			old, _ := d.GetChange(key)
+			err = d.SetNew(key, old)
+		default:
+			err = d.SetNew(key, value.AsString())
+		}
+
+		if err != nil {
+			return false, fmt.Errorf("could not set %s: %+v", key, err)
+		}
+	}
+
+	return known, nil
+}
+
+// setNewLeapfrogValues sets the labels, and the active side and the active and inactive values for the given alpha.
+// The active and inactive values are marked as computed when the labels are unknown during plan.
+func setNewLeapfrogValues(d *schema.ResourceDiff, alpha bool) error {
+	known, err := setNewLeapfrogLabels(d)
+	if err != nil {
+		return err
+	}
+
+	if known {
+		return setLeapfrogValues(d.SetNew, d, alpha)
+	}
+
+	for _, key := range []string{"active_side", "active_value", "inactive_value"} {
+		if err := d.SetNewComputed(key); err != nil {
+			return fmt.Errorf("could not mark %s as new computed: %+v", key, err)
+		}
+	}
+
+	return nil
+}
+
+// setNewComputedLeapfrogTimestamps marks the timestamps of alpha and beta as new computed, they are set in
+// resourceLeapfrogUpdate.
+func setNewComputedLeapfrogTimestamps(d *schema.ResourceDiff, alpha bool, beta bool) error {
+	for _, side := range []string{"alpha", "beta"} {
+		if (side == "alpha" && !alpha) || (side == "beta" && !beta) {
+			continue
+		}
+
+		// SetNewComputed of the timestamp also clears the diff of its unix companion, so it is marked last.
+		for _, key := range []string{side + "_timestamp", side + "_timestamp_unix"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return fmt.Errorf("could not mark %s as new computed: %+v", key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// setLeapfrogTimestamps sets the timestamps of alpha and beta that are unknown in the plan, in the timestamp format.
+// The timestamp of the side that was toggled to active is set to now, the other timestamps are only reformatted.
+func setLeapfrogTimestamps(d *schema.ResourceData, alpha bool, toggled bool, now time.Time) error {
+	plan := d.GetRawPlan()
+	oldFormat, format := d.GetChange("timestamp_format")
+
+	for _, side := range []string{"alpha", "beta"} {
+		key := side + "_timestamp"
+		if plan.GetAttr(key).IsKnown() {
+			continue
+		}
+
+		timestamp := now
+		if !toggled || (side == "alpha") != alpha {
+			old, _ := d.GetChange(key)
+
+			var err error
+			if timestamp, err = parseTimestamp(old.(string), oldFormat.(string)); err != nil {
+				return fmt.Errorf("could not parse %s: %+v", key, err)
+			}
+		}
+
+		if err := d.Set(key, formatTimestamp(timestamp, format.(string))); err != nil {
+			return fmt.Errorf("could not set %s: %+v", key, err)
+		}
+
+		if err := d.Set(key+"_unix", int(timestamp.Unix())); err != nil {
+			return fmt.Errorf("could not set %s_unix: %+v", key, err)
+		}
+	}
+
+	return nil
+}
+
+// leapfrogTriggerMode returns the trigger mode of a leapfrog.
+// Unlike the other toggles, a leapfrog with a rotation interval but without a trigger or triggers is not toggled on each
+// apply, but only when the rotation interval elapses, unless the provider sets a default trigger mode.
+func leapfrogTriggerMode(d resourceGetter, meta *providerMeta) string {
+	if configuredTriggerMode(d, meta) == "" && d.Get("rotation_interval").(string) != "" {
+		return triggerModeOnChange
+	}
+
+	return triggerMode(d, meta)
+}
+
+// leapfrogIntervalElapsed returns whether the rotation interval has elapsed at now since the active side, which is
+// alpha or not, was last updated.
+func leapfrogIntervalElapsed(d resourceGetter, alpha bool, now time.Time) (bool, error) {
+	rotationInterval := d.Get("rotation_interval").(string)
+	if rotationInterval == "" {
+		return false, nil
+	}
+
+	interval, err := time.ParseDuration(rotationInterval)
+	if err != nil {
+		return false, fmt.Errorf("could not parse rotation_interval: %+v", err)
+	}
+
+	key := "alpha_timestamp"
+	if !alpha {
+		key = "beta_timestamp"
+	}
+
+	format, _ := d.GetChange("timestamp_format")
+	rawTimestamp, _ := d.GetChange(key)
+	timestamp, err := parseTimestamp(rawTimestamp.(string), format.(string))
+	if err != nil {
+		return false, fmt.Errorf("could not parse %s: %+v", key, err)
+	}
+
+	return !now.Before(timestamp.Add(interval)), nil
+}
+
+// leapfrogToggle returns the reason to toggle a leapfrog, which currently has alpha active or not, now. The reason is
+// empty when it should not be toggled.
+// A forced active side overrides the triggers and the rotation interval, and only toggles to that side. When both the
+// triggers and the rotation interval would toggle the output, the reason is the trigger. The never trigger mode also
+// prevents the rotation interval from toggling the output. A frozen provider prevents all of them.
+func leapfrogToggle(d resourceGetter, alpha bool, meta *providerMeta) (string, error) {
+	if meta.Frozen {
+		return "", nil
+	}
+
+	if forceActive := d.Get("force_active").(string); forceActive != "" {
+		if (forceActive == "alpha") != alpha {
+			return leapfrogReasonForceActive, nil
+		}
+
+		return "", nil
+	}
+
+	mode := leapfrogTriggerMode(d, meta)
+	if shouldToggleInMode(d, mode) {
+		return leapfrogReasonTrigger, nil
+	}
+
+	if mode == triggerModeNever {
+		return "", nil
+	}
+
+	elapsed, err := leapfrogIntervalElapsed(d, alpha, meta.Clock())
+	if err != nil || !elapsed {
+		return "", err
+	}
+
+	return leapfrogReasonRotationInterval, nil
+}
+
+// suppressedLeapfrogToggle describes the change that would have toggled a leapfrog, which currently has alpha active or
+// not, if the provider was not frozen. It is empty when the provider is not frozen or the leapfrog would not toggle.
+func suppressedLeapfrogToggle(d resourceGetter, alpha bool, meta *providerMeta) (string, error) {
+	if !meta.Frozen {
+		return "", nil
+	}
+
+	thawed := meta.thawed()
+	reason, err := leapfrogToggle(d, alpha, thawed)
+	if err != nil {
+		return "", err
+	}
+
+	switch reason {
+	case "":
+		return "", nil
+	case leapfrogReasonForceActive:
+		return fmt.Sprintf("force_active %q", d.Get("force_active")), nil
+	case leapfrogReasonRotationInterval:
+		return fmt.Sprintf("the elapsed rotation_interval %s", d.Get("rotation_interval")), nil
+	default:
+		return describeTrigger(d, leapfrogTriggerMode(d, thawed)), nil
+	}
+}
+
 // customizeDiffLeapfrog ensures that we show changes in the diff phase.
 // During creation it is responsive for setting the initial values of alpha and beta.
 // During an update it is responsible for toggling alpha and beta, and marking the timestamps with new computed values,
-// in a leapfrog fashion.
-func customizeDiffLeapfrog(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+// in a leapfrog fashion. When the provider is frozen, a suppressed toggle is reported according to the freeze behavior.
+func customizeDiffLeapfrog(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	meta := getProviderMeta(m)
+
 	// New resource: only set alpha and beta now. The timestamps are set in resourceLeapfrogCreate
 	if d.Id() == "" {
-		if err := d.SetNew("alpha", true); err != nil {
+		alpha := d.Get("force_active").(string) != "beta"
+
+		if err := d.SetNew("alpha", alpha); err != nil {
 			return fmt.Errorf("could not set alpha: %+v", err)
 		}
 
-		if err := d.SetNew("beta", false); err != nil {
+		if err := d.SetNew("beta", !alpha); err != nil {
 			return fmt.Errorf("could not set beta: %+v", err)
 		}
 
-		return nil
+		if err := d.SetNew("last_trigger_keys", []interface{}{}); err != nil {
+			return fmt.Errorf("could not set last_trigger_keys: %+v", err)
+		}
+
+		if err := d.SetNew("last_toggle_reason", ""); err != nil {
+			return fmt.Errorf("could not set last_toggle_reason: %+v", err)
+		}
+
+		if err := setNewDefaultTimestampFormat(d, meta); err != nil {
+			return err
+		}
+
+		return setNewLeapfrogValues(d, alpha)
+	}
+
+	if err := clearLastTriggerKeys(d); err != nil {
+		return err
+	}
+
+	// When the triggers, the forced side or the rotation interval are unknown during plan, we can only determine whether
+	// to toggle during apply.
+	if !triggersKnown(d, meta) || !d.NewValueKnown("force_active") || !d.NewValueKnown("rotation_interval") {
+		for _, key := range []string{"alpha", "beta", "alpha_timestamp", "beta_timestamp", "active_side", "active_value", "inactive_value", "last_trigger_keys", "last_toggle_reason", "alpha_timestamp_unix", "beta_timestamp_unix"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return fmt.Errorf("could not mark %s as new computed: %+v", key, err)
+			}
+		}
+
+		_, err := setNewLeapfrogLabels(d)
+		return err
+	}
+
+	reason, err := leapfrogToggle(d, d.Get("alpha").(bool), meta)
+	if err != nil {
+		return err
 	}
 
-	// If the trigger is set, but does not have a change, we shouldn't change anything.
-	// If the trigger is empty, always update.
-	trigger := d.Get("trigger").(string)
-	if trigger != "" && !d.HasChange("trigger")  {
-		return nil
+	suppressed, err := suppressedLeapfrogToggle(d, d.Get("alpha").(bool), meta)
+	if err != nil {
+		return err
+	}
+
+	if suppressed != "" {
+		if err := reportSuppressedToggle(meta, suppressedToggleMessage("toggles_leapfrog", d.Id(), suppressed)); err != nil {
+			return err
+		}
+	}
+
+	// The timestamps of both sides are reformatted when the timestamp format changes.
+	formatChanged := timestampFormatChanged(d)
+
+	if reason == "" {
+		if err := setNewLeapfrogValues(d, d.Get("alpha").(bool)); err != nil {
+			return err
+		}
+
+		return setNewComputedLeapfrogTimestamps(d, formatChanged, formatChanged)
 	}
 
 	alpha := d.Get("alpha").(bool)
@@ -84,19 +492,21 @@ func customizeDiffLeapfrog(_ context.Context, d *schema.ResourceDiff, _ interfac
 		return fmt.Errorf("could not set beta: %+v", err)
 	}
 
-	if alpha {
-		if err := d.SetNewComputed("alpha_timestamp"); err != nil {
-			return fmt.Errorf("could not mark alpha_timestamp as new computed: %+v", err)
-		}
+	if err := d.SetNew("last_toggle_reason", reason); err != nil {
+		return fmt.Errorf("could not set last_toggle_reason: %+v", err)
 	}
 
-	if beta {
-		if err := d.SetNewComputed("beta_timestamp"); err != nil {
-			return fmt.Errorf("could not mark beta_timestamp as new computed: %+v", err)
+	if reason == leapfrogReasonTrigger {
+		if err := setLastTriggerKeys(d.SetNew, d); err != nil {
+			return err
 		}
 	}
 
-	return nil
+	if err := setNewLeapfrogValues(d, alpha); err != nil {
+		return err
+	}
+
+	return setNewComputedLeapfrogTimestamps(d, alpha || formatChanged, beta || formatChanged)
 }
 
 // resourceLeapfrogCreate set the initial timestamps.
@@ -104,18 +514,25 @@ func customizeDiffLeapfrog(_ context.Context, d *schema.ResourceDiff, _ interfac
 func resourceLeapfrogCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	now := time.Now().Format(time.RFC3339)
+	now := getProviderMeta(m).now()
+	format := d.Get("timestamp_format").(string)
+
+	for _, key := range []string{"alpha_timestamp", "beta_timestamp"} {
+		if err := d.Set(key, formatTimestamp(now, format)); err != nil {
+			diags = diag.Errorf("could not set %s: %+v", key, err)
+		}
 
-	if err := d.Set("alpha_timestamp", now); err != nil {
-		diags = diag.Errorf("could not set alpha_timestamp: %+v", err)
+		if err := d.Set(key+"_unix", int(now.Unix())); err != nil {
+			diags = diag.Errorf("could not set %s_unix: %+v", key, err)
+		}
 	}
 
-	if err := d.Set("beta_timestamp", now); err != nil {
-		diags = diag.Errorf("could not set beta_timestamp: %+v", err)
+	id, err := newToggleID()
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	// Not important
-	d.SetId("toggle")
+	d.SetId(id)
 
 	return diags
 }
@@ -128,26 +545,85 @@ func resourceLeapfrogRead(ctx context.Context, d *schema.ResourceData, m interfa
 }
 
 // resourceLeapfrogUpdate updates the timestamps depending on whether alpha or beta is active.
+// When the trigger was unknown during plan, it also determines whether alpha and beta are toggled and sets the values.
 func resourceLeapfrogUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics  {
 	var diags diag.Diagnostics
 
+	meta := getProviderMeta(m)
+
 	alpha := d.Get("alpha").(bool)
 	beta := d.Get("beta").(bool)
 
-	now := time.Now().Format(time.RFC3339)
+	plan := d.GetRawPlan()
+	toggled := d.HasChange("alpha")
+	oldAlpha, _ := d.GetChange("alpha")
+
+	// CustomizeDiff can only log a warning, so a suppressed toggle is also reported as a diagnostic of the apply.
+	suppressed, err := suppressedLeapfrogToggle(d, oldAlpha.(bool), meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	if alpha {
-		if err := d.Set("alpha_timestamp", now); err != nil {
-			diags = diag.Errorf("could not set alpha_timestamp: %+v", err)
+	if suppressed != "" {
+		diags = append(diags, suppressedToggleDiagnostics(meta, suppressedToggleMessage("toggles_leapfrog", d.Id(), suppressed))...)
+		if diags.HasError() {
+			return diags
 		}
 	}
 
-	if beta {
-		if err := d.Set("beta_timestamp", now); err != nil {
-			diags = diag.Errorf("could not set beta_timestamp: %+v", err)
+	if !plan.GetAttr("alpha").IsKnown() {
+		reason, err := leapfrogToggle(d, oldAlpha.(bool), meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		toggled = reason != ""
+		alpha = oldAlpha.(bool) != toggled
+		beta = !alpha
+
+		if err := d.Set("alpha", alpha); err != nil {
+			return diag.Errorf("could not set alpha: %+v", err)
+		}
+
+		if err := d.Set("beta", beta); err != nil {
+			return diag.Errorf("could not set beta: %+v", err)
+		}
+
+		// The last trigger keys and the last toggle reason were marked as computed, restore them in case they don't
+		// change. The timestamps are set below.
+		for _, key := range []string{"last_trigger_keys", "last_toggle_reason"} {
+			old, _ := d.GetChange(key)
+			if err := d.Set(key, old); err != nil {
+				return diag.Errorf("could not set %s: %+v", key, err)
+			}
+		}
+
+		if toggled {
+			if err := d.Set("last_toggle_reason", reason); err != nil {
+				return diag.Errorf("could not set last_toggle_reason: %+v", err)
+			}
+		}
+
+		if reason == leapfrogReasonTrigger {
+			if err := setLastTriggerKeys(d.Set, d); err != nil {
+				return diag.FromErr(err)
+			}
 		}
 	}
 
+	// The values are also unknown when the labels were unknown during plan.
+	if !plan.GetAttr("active_value").IsKnown() {
+		if err := setLeapfrogValues(d.Set, d, alpha); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// Only the timestamp of the side that was toggled to active, or all timestamps when the timestamp format changed,
+	// are marked as computed during plan.
+	if err := setLeapfrogTimestamps(d, alpha, toggled, meta.now()); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return diags
 }
 
@@ -157,3 +633,98 @@ func resourceLeapfrogDelete(ctx context.Context, d *schema.ResourceData, m inter
 
 	return diags
 }
+
+// resourceLeapfrogImport restores the state of a leapfrog from the import ID.
+// The ID has the format <active>,<alpha_timestamp>,<beta_timestamp>[,triggers=<triggers>][,<trigger>], where active is
+// either alpha or beta, the timestamps are RFC3339 timestamps and the triggers are a JSON object. The optional trigger
+// and triggers should equal the configured ones, otherwise the next apply will toggle the output.
+func resourceLeapfrogImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ",", 4)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("unexpected format of ID (%s), expected <active>,<alpha_timestamp>,<beta_timestamp>[,triggers=<triggers>][,<trigger>]", d.Id())
+	}
+
+	active := parts[0]
+	if active != "alpha" && active != "beta" {
+		return nil, fmt.Errorf("invalid active output (%s), expected alpha or beta", active)
+	}
+
+	alphaTimestamp := parts[1]
+	alphaTime, err := time.Parse(time.RFC3339, alphaTimestamp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alpha_timestamp (%s), expected an RFC3339 timestamp: %+v", alphaTimestamp, err)
+	}
+
+	betaTimestamp := parts[2]
+	betaTime, err := time.Parse(time.RFC3339, betaTimestamp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid beta_timestamp (%s), expected an RFC3339 timestamp: %+v", betaTimestamp, err)
+	}
+
+	if len(parts) == 4 {
+		trigger, hasTrigger := parts[3], true
+		if strings.HasPrefix(trigger, importTriggersPrefix) {
+			triggers, rest, err := parseImportTriggers(strings.TrimPrefix(trigger, importTriggersPrefix))
+			if err != nil {
+				return nil, err
+			}
+
+			if rest != "" && !strings.HasPrefix(rest, ",") {
+				return nil, fmt.Errorf("unexpected characters (%s) after the triggers, expected ,<trigger>", rest)
+			}
+
+			if err := d.Set("triggers", triggers); err != nil {
+				return nil, fmt.Errorf("could not set triggers: %+v", err)
+			}
+
+			trigger, hasTrigger = strings.TrimPrefix(rest, ","), rest != ""
+		}
+
+		if hasTrigger {
+			if err := d.Set("trigger", trigger); err != nil {
+				return nil, fmt.Errorf("could not set trigger: %+v", err)
+			}
+		}
+	}
+
+	if err := d.Set("alpha", active == "alpha"); err != nil {
+		return nil, fmt.Errorf("could not set alpha: %+v", err)
+	}
+
+	if err := d.Set("beta", active == "beta"); err != nil {
+		return nil, fmt.Errorf("could not set beta: %+v", err)
+	}
+
+	if err := setLeapfrogValues(d.Set, d, active == "alpha"); err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("alpha_timestamp", alphaTimestamp); err != nil {
+		return nil, fmt.Errorf("could not set alpha_timestamp: %+v", err)
+	}
+
+	if err := d.Set("beta_timestamp", betaTimestamp); err != nil {
+		return nil, fmt.Errorf("could not set beta_timestamp: %+v", err)
+	}
+
+	if err := d.Set("timestamp_format", timestampFormatRFC3339Nano); err != nil {
+		return nil, fmt.Errorf("could not set timestamp_format: %+v", err)
+	}
+
+	if err := d.Set("alpha_timestamp_unix", int(alphaTime.Unix())); err != nil {
+		return nil, fmt.Errorf("could not set alpha_timestamp_unix: %+v", err)
+	}
+
+	if err := d.Set("beta_timestamp_unix", int(betaTime.Unix())); err != nil {
+		return nil, fmt.Errorf("could not set beta_timestamp_unix: %+v", err)
+	}
+
+	id, err := newToggleID()
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(id)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,161 @@
+package toggles
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceLeapfrogV0 is the schema of version 0 of the leapfrog resource.
+func resourceLeapfrogV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema {
+			"trigger": {
+				Type: schema.TypeString,
+				Optional: true,
+			},
+			"alpha_timestamp": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"beta_timestamp": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"alpha": {
+				Type: schema.TypeBool,
+				Computed: true,
+			},
+			"beta": {
+				Type: schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceLeapfrogStateUpgradeV0 replaces the constant "toggle" ID that was used by version 0 with a unique ID, which
+// is derived from the prior state.
+func resourceLeapfrogStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if rawState["id"] == legacyToggleID {
+		id, err := migratedToggleID(rawState)
+		if err != nil {
+			return nil, err
+		}
+
+		rawState["id"] = id
+	}
+
+	return rawState, nil
+}
+
+// resourceLeapfrogV1 is the schema of version 1 of the leapfrog resource.
+func resourceLeapfrogV1() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema {
+			"trigger": {
+				Type: schema.TypeString,
+				Optional: true,
+			},
+			"triggers": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+			},
+			"trigger_mode": {
+				Type: schema.TypeString,
+				Optional: true,
+			},
+			"force_active": {
+				Type: schema.TypeString,
+				Optional: true,
+			},
+			"rotation_interval": {
+				Type: schema.TypeString,
+				Optional: true,
+			},
+			"last_toggle_reason": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"last_trigger_keys": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"alpha_value": {
+				Type: schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"beta_value": {
+				Type: schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"active_side": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"active_value": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"inactive_value": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"alpha_timestamp": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"beta_timestamp": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"alpha": {
+				Type: schema.TypeBool,
+				Computed: true,
+			},
+			"beta": {
+				Type: schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceLeapfrogStateUpgradeV1 keeps the RFC3339 timestamps of existing leapfrogs, as version 2 only defaults to RFC3339
+// timestamps with nanoseconds for new leapfrogs, and sets their unix timestamps. It also sets the active side and values
+// of leapfrogs that were created before they were added.
+func resourceLeapfrogStateUpgradeV1(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	rawState["timestamp_format"] = timestampFormatRFC3339
+
+	if _, ok := rawState["active_side"].(string); !ok {
+		alphaValue, _ := rawState["alpha_value"].(string)
+		betaValue, _ := rawState["beta_value"].(string)
+
+		if alpha, _ := rawState["alpha"].(bool); alpha {
+			rawState["active_side"], rawState["active_value"], rawState["inactive_value"] = "alpha", alphaValue, betaValue
+		} else {
+			rawState["active_side"], rawState["active_value"], rawState["inactive_value"] = "beta", betaValue, alphaValue
+		}
+	}
+
+	for _, key := range []string{"alpha_timestamp", "beta_timestamp"} {
+		timestamp, _ := rawState[key].(string)
+
+		unix, err := unixTimestamp(timestamp, timestampFormatRFC3339)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %+v", key, err)
+		}
+
+		rawState[key+"_unix"] = unix
+	}
+
+	return rawState, nil
+}
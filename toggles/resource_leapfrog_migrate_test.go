@@ -0,0 +1,107 @@
+package toggles
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func testResourceLeapfrogStateDataV0() map[string]interface{} {
+	return map[string]interface{}{
+		"id": "toggle",
+		"trigger": "initial",
+		"alpha_timestamp": "2021-01-01T00:00:00Z",
+		"beta_timestamp": "2021-01-02T00:00:00Z",
+		"alpha": false,
+		"beta": true,
+	}
+}
+
+func TestResourceLeapfrogStateUpgradeV0(t *testing.T) {
+	expected := testResourceLeapfrogStateDataV0()
+
+	actual, err := resourceLeapfrogStateUpgradeV0(context.Background(), testResourceLeapfrogStateDataV0(), nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	// The constant ID should be replaced by a unique ID
+	if !testAccUUIDRegexp.MatchString(actual["id"].(string)) {
+		t.Fatalf("expected a unique id, got %s", actual["id"])
+	}
+
+	// All other attributes should be left untouched
+	delete(expected, "id")
+	delete(actual, "id")
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("\n\nexpected:\n\n%#v\n\ngot:\n\n%#v\n\n", expected, actual)
+	}
+}
+
+func TestResourceLeapfrogStateUpgradeV0_deterministicId(t *testing.T) {
+	first, err := resourceLeapfrogStateUpgradeV0(context.Background(), testResourceLeapfrogStateDataV0(), nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	second, err := resourceLeapfrogStateUpgradeV0(context.Background(), testResourceLeapfrogStateDataV0(), nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	// Upgrading the same state again, as every plan does until the state is stored, should result in the same ID
+	if first["id"] != second["id"] {
+		t.Fatalf("expected the same id, got %s and %s", first["id"], second["id"])
+	}
+
+	rawState := testResourceLeapfrogStateDataV0()
+	rawState["trigger"] = "other"
+
+	other, err := resourceLeapfrogStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	// Toggles with a different state should get a different ID
+	if first["id"] == other["id"] {
+		t.Fatalf("expected a different id, got %s for both", first["id"])
+	}
+}
+
+func TestResourceLeapfrogStateUpgradeV0_uniqueId(t *testing.T) {
+	rawState := testResourceLeapfrogStateDataV0()
+	rawState["id"] = "2f2a5feb-fa68-c59d-18e9-25ee67d8ac84"
+
+	actual, err := resourceLeapfrogStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	// An ID that is already unique should be kept
+	if actual["id"] != "2f2a5feb-fa68-c59d-18e9-25ee67d8ac84" {
+		t.Fatalf("expected the id to be kept, got %s", actual["id"])
+	}
+}
+
+func TestResourceLeapfrogStateUpgradeV1(t *testing.T) {
+	rawState := testResourceLeapfrogStateDataV0()
+
+	actual, err := resourceLeapfrogStateUpgradeV1(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	// Existing leapfrogs should keep their RFC3339 timestamps, get unix timestamps and the active side and values
+	expected := testResourceLeapfrogStateDataV0()
+	expected["timestamp_format"] = "rfc3339"
+	expected["alpha_timestamp_unix"] = 1609459200
+	expected["beta_timestamp_unix"] = 1609545600
+	expected["active_side"] = "beta"
+	expected["active_value"] = ""
+	expected["inactive_value"] = ""
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("\n\nexpected:\n\n%#v\n\ngot:\n\n%#v\n\n", expected, actual)
+	}
+}
@@ -3,29 +3,32 @@ package toggles
 import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"regexp"
 	"testing"
 )
 
 func TestAccLeapfrog(t *testing.T) {
+	defer testAccRestoreTime()
+
 	resource.Test(t, resource.TestCase{
 		ProviderFactories: testAccProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				// Applying the resource for the first time should set alpha to active and initialize both timestamps
 				// with equal values.
-				PreConfig: sleep,
+				PreConfig: testAccTick,
 				Config: testAccLeapfrogResource("initial"),
 				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("toggles_leapfrog.test", "id", testAccUUIDRegexp),
 					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
 					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "false"),
-					resource.TestCheckResourceAttrPair("toggles_leapfrog.test", "alpha_timestamp", "toggles_leapfrog.test", "beta_timestamp"),
-					testAccValidRFC3339("toggles_leapfrog.test", "alpha_timestamp"),
-					testAccValidRFC3339("toggles_leapfrog.test", "beta_timestamp"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2021-01-01T00:00:01Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp", "2021-01-01T00:00:01Z"),
 				),
 			},
 			{
 				// Re-applying the resource with an un-changed trigger value should have the same output.
-				PreConfig: sleep,
+				PreConfig: testAccTick,
 				Config: testAccLeapfrogResource("initial"),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
@@ -37,17 +40,50 @@ func TestAccLeapfrog(t *testing.T) {
 			},
 			{
 				// Re-applying the resource with a changed trigger value should mark beta as active.
-				PreConfig: sleep,
-				Config: testAccLeapfrogResource("change-1"),
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithValues("change-1", "", ""),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "false"),
 					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
-					testAccTimeAfter("toggles_leapfrog.test", "beta_timestamp", "toggles_leapfrog.test", "alpha_timestamp"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2021-01-01T00:00:01Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp", "2021-01-01T00:00:03Z"),
 				),
 			},
+			{
+				// Importing the resource should restore the active side, the timestamps and the trigger encoded in the
+				// ID, and generate a new unique ID.
+				ResourceName: "toggles_leapfrog.test",
+				ImportState: true,
+				ImportStateId: "beta,2021-01-01T00:00:00Z,2021-01-02T00:00:00Z,change-1",
+				ImportStateCheck: testAccImportStateCheckAttributes(map[string]string{
+					"alpha": "false",
+					"beta": "true",
+					"active_side": "beta",
+					"alpha_timestamp": "2021-01-01T00:00:00Z",
+					"beta_timestamp": "2021-01-02T00:00:00Z",
+					"alpha_timestamp_unix": "1609459200",
+					"beta_timestamp_unix": "1609545600",
+					"timestamp_format": "rfc3339nano",
+					"trigger": "change-1",
+				}),
+			},
+			{
+				// Importing the resource with an invalid active side should fail.
+				ResourceName: "toggles_leapfrog.test",
+				ImportState: true,
+				ImportStateId: "gamma,2021-01-01T00:00:00Z,2021-01-02T00:00:00Z",
+				ExpectError: regexp.MustCompile("invalid active output"),
+			},
+			{
+				// Importing the resource with an invalid timestamp should fail.
+				ResourceName: "toggles_leapfrog.test",
+				ImportState: true,
+				ImportStateId: "beta,2021-01-01T00:00:00Z,yesterday",
+				ExpectError: regexp.MustCompile("invalid beta_timestamp"),
+			},
 			{
 				// Re-applying the resource with a changed trigger value again should mark alpha as active again.
-				PreConfig: sleep,
+				PreConfig: testAccTick,
 				Config: testAccLeapfrogResource("change-2"),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
@@ -59,6 +95,513 @@ func TestAccLeapfrog(t *testing.T) {
 	})
 }
 
+func TestAccLeapfrog_unknownTrigger(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Creating the resource with a trigger that is unknown during plan should set alpha to active.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithUnknownTrigger("initial"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "false"),
+					resource.TestCheckResourceAttrPair("toggles_leapfrog.test", "alpha_timestamp", "toggles_leapfrog.test", "beta_timestamp"),
+				),
+			},
+			{
+				// Re-applying the resource with an un-changed trigger should have the same output.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithUnknownTrigger("initial"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "false"),
+					resource.TestCheckResourceAttrPair("toggles_leapfrog.test", "alpha_timestamp", "toggles_leapfrog.test", "beta_timestamp"),
+				),
+			},
+			{
+				// Re-applying the resource with a trigger that is unknown during plan, and changes during apply, should
+				// mark beta as active and only update the beta timestamp.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithUnknownTrigger("change-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "false"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
+					testAccValidRFC3339("toggles_leapfrog.test", "alpha_timestamp"),
+					testAccTimeAfter("toggles_leapfrog.test", "beta_timestamp", "toggles_leapfrog.test", "alpha_timestamp"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLeapfrog_triggers(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should set alpha to active without any trigger keys.
+				Config: testAccLeapfrogResourceWithTriggers(`a = "1", b = "1"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "false"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "last_trigger_keys.#", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with un-changed triggers should have the same output.
+				Config: testAccLeapfrogResourceWithTriggers(`a = "1", b = "1"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "false"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "last_trigger_keys.#", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger should mark beta as active and record the changed key.
+				Config: testAccLeapfrogResourceWithTriggers(`a = "1", b = "2"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "false"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "last_trigger_keys.#", "1"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "last_trigger_keys.0", "b"),
+				),
+			},
+			{
+				// Re-applying the resource with a removed and an added trigger should mark alpha as active and record both
+				// keys.
+				Config: testAccLeapfrogResourceWithTriggers(`b = "2", c = "1"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "false"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "last_trigger_keys.#", "2"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "last_trigger_keys.0", "a"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "last_trigger_keys.1", "c"),
+				),
+			},
+			{
+				// Importing the resource should restore the triggers encoded in the ID, followed by the trigger, which
+				// may contain commas.
+				ResourceName: "toggles_leapfrog.test",
+				ImportState: true,
+				ImportStateId: `alpha,2021-01-01T00:00:00Z,2021-01-02T00:00:00Z,triggers={"b":"2","c":"1"},x,y`,
+				ImportStateCheck: testAccImportStateCheckAttributes(map[string]string{
+					"alpha": "true",
+					"active_side": "alpha",
+					"triggers.%": "2",
+					"triggers.b": "2",
+					"triggers.c": "1",
+					"trigger": "x,y",
+				}),
+			},
+			{
+				// Importing the resource with triggers that are not a JSON object of strings should fail.
+				ResourceName: "toggles_leapfrog.test",
+				ImportState: true,
+				ImportStateId: `alpha,2021-01-01T00:00:00Z,2021-01-02T00:00:00Z,triggers={"b":2}`,
+				ExpectError: regexp.MustCompile("invalid triggers"),
+			},
+		},
+	})
+}
+
+func TestAccLeapfrog_triggerMode(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should set alpha to active.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithTriggerMode("on_change", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "false"),
+				),
+			},
+			{
+				// Applying the resource with an invalid trigger mode should fail.
+				Config: testAccLeapfrogResourceWithTriggerMode("sometimes", ""),
+				ExpectError: regexp.MustCompile("expected trigger_mode to be one of"),
+			},
+			{
+				// Re-applying the resource with an un-changed empty trigger should not toggle in on_change mode.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithTriggerMode("on_change", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "false"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger should mark beta as active.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithTriggerMode("on_change", "change-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "false"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
+				),
+			},
+			{
+				// Re-applying the resource with a trigger changed to an empty value should mark alpha as active in on_change
+				// mode.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithTriggerMode("on_change", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "false"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger should not toggle in never mode.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithTriggerMode("never", "change-2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "false"),
+				),
+			},
+			{
+				// Re-applying the resource with an un-changed trigger should mark beta as active in always mode, and toggle
+				// again on the next plan.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithTriggerMode("always", "change-2"),
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "false"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLeapfrog_values(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should set the alpha value as active.
+				Config: testAccLeapfrogResourceWithValues("initial", "blue", "green"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_side", "alpha"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_value", "blue"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "inactive_value", "green"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger should set the beta value as active.
+				Config: testAccLeapfrogResourceWithValues("change-1", "blue", "green"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_side", "beta"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_value", "green"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "inactive_value", "blue"),
+				),
+			},
+			{
+				// Re-applying the resource with changed values should update the values without toggling.
+				Config: testAccLeapfrogResourceWithValues("change-1", "red", "yellow"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_side", "beta"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_value", "yellow"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "inactive_value", "red"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger and changed values should set the new alpha value as active.
+				Config: testAccLeapfrogResourceWithValues("change-2", "cyan", "magenta"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_value", "cyan"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_side", "alpha"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_value", "cyan"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "inactive_value", "magenta"),
+				),
+			},
+			{
+				// Removing a value from the configuration should keep its last value, and result in an empty plan.
+				Config: `
+resource "toggles_leapfrog" "test" {
+  trigger = "change-2"
+  beta_value = "magenta"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_value", "cyan"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_value", "cyan"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "inactive_value", "magenta"),
+				),
+			},
+			{
+				Config: `
+resource "toggles_leapfrog" "test" {
+  trigger = "change-2"
+  beta_value = "magenta"
+}
+`,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccLeapfrog_unknownValues(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Creating the resource with values that are unknown during plan should set the values during apply.
+				Config: testAccLeapfrogResourceWithUnknownValues("initial", "initial"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_side", "alpha"),
+					resource.TestCheckResourceAttrPair("toggles_leapfrog.test", "active_value", "toggles_leapfrog.values", "alpha_timestamp"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "inactive_value", "standby"),
+				),
+			},
+			{
+				// Re-applying the resource with values that are unknown during plan, and a changed trigger, should set the
+				// values during apply.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithUnknownValues("change-1", "change-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_side", "beta"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_value", "standby"),
+					resource.TestCheckResourceAttrPair("toggles_leapfrog.test", "inactive_value", "toggles_leapfrog.values", "beta_timestamp"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLeapfrog_forceActive(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time with a forced side should set that side to active.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithForceActive("initial", `"beta"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "false"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_side", "beta"),
+				),
+			},
+			{
+				// Applying the resource with an invalid forced side should fail.
+				Config: testAccLeapfrogResourceWithForceActive("initial", `"gamma"`),
+				ExpectError: regexp.MustCompile("expected force_active to be one of"),
+			},
+			{
+				// Re-applying the resource with a changed trigger should keep the forced side active.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithForceActive("change-1", `"beta"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "false"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
+					resource.TestCheckResourceAttrPair("toggles_leapfrog.test", "alpha_timestamp", "toggles_leapfrog.test", "beta_timestamp"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed forced side should mark alpha as active and only update the alpha
+				// timestamp.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithForceActive("change-1", `"alpha"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "false"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "active_side", "alpha"),
+					testAccTimeAfter("toggles_leapfrog.test", "alpha_timestamp", "toggles_leapfrog.test", "beta_timestamp"),
+				),
+			},
+			{
+				// Re-applying the resource without a forced side and an un-changed trigger should keep alpha active.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithForceActive("change-1", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "false"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger should resume toggling and mark beta as active.
+				PreConfig: testAccTick,
+				Config: testAccLeapfrogResourceWithForceActive("change-2", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "false"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
+					testAccTimeAfter("toggles_leapfrog.test", "beta_timestamp", "toggles_leapfrog.test", "alpha_timestamp"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLeapfrog_rotationInterval(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time with a rotation interval should set alpha to active.
+				PreConfig: testAccFixTime("2021-01-01T00:00:00Z"),
+				Config: testAccLeapfrogResourceWithRotationInterval("", "1h"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2021-01-01T00:00:00Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp", "2021-01-01T00:00:00Z"),
+					resource.TestCheckNoResourceAttr("toggles_leapfrog.test", "last_toggle_reason"),
+				),
+			},
+			{
+				// Applying the resource with an invalid rotation interval should fail.
+				PreConfig: testAccFixTime("2021-01-01T00:00:00Z"),
+				Config: testAccLeapfrogResourceWithRotationInterval("", "-1h"),
+				ExpectError: regexp.MustCompile("expected rotation_interval to be a positive duration"),
+			},
+			{
+				// Re-applying the resource without a trigger before the rotation interval elapsed should not toggle.
+				PreConfig: testAccFixTime("2021-01-01T00:59:59Z"),
+				Config: testAccLeapfrogResourceWithRotationInterval("", "1h"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2021-01-01T00:00:00Z"),
+				),
+			},
+			{
+				// Re-applying the resource after the rotation interval elapsed should mark beta as active.
+				PreConfig: testAccFixTime("2021-01-01T01:00:00Z"),
+				Config: testAccLeapfrogResourceWithRotationInterval("", "1h"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2021-01-01T00:00:00Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp", "2021-01-01T01:00:00Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "last_toggle_reason", "rotation_interval"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger before the rotation interval elapsed should mark alpha
+				// as active.
+				PreConfig: testAccFixTime("2021-01-01T01:30:00Z"),
+				Config: testAccLeapfrogResourceWithRotationInterval("change-1", "1h"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2021-01-01T01:30:00Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "last_toggle_reason", "trigger"),
+				),
+			},
+			{
+				// Re-applying the resource before the rotation interval elapsed since the last toggle should not toggle.
+				PreConfig: testAccFixTime("2021-01-01T02:29:59Z"),
+				Config: testAccLeapfrogResourceWithRotationInterval("change-1", "1h"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "last_toggle_reason", "trigger"),
+				),
+			},
+			{
+				// Re-applying the resource with a longer rotation interval should take the new interval into account.
+				PreConfig: testAccFixTime("2021-01-01T02:30:00Z"),
+				Config: testAccLeapfrogResourceWithRotationInterval("change-1", "2h"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2021-01-01T01:30:00Z"),
+				),
+			},
+			{
+				// Re-applying the resource after the rotation interval elapsed should mark beta as active again.
+				PreConfig: testAccFixTime("2021-01-01T03:30:00Z"),
+				Config: testAccLeapfrogResourceWithRotationInterval("change-1", "2h"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp", "2021-01-01T03:30:00Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "last_toggle_reason", "rotation_interval"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLeapfrog_timestampFormat(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should default to timestamps with nanoseconds.
+				PreConfig: testAccFixTime("2021-01-01T00:00:00.123456789Z"),
+				Config: testAccLeapfrogResourceWithTimestampFormat("initial", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "timestamp_format", "rfc3339nano"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2021-01-01T00:00:00.123456789Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp", "2021-01-01T00:00:00.123456789Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp_unix", "1609459200"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp_unix", "1609459200"),
+				),
+			},
+			{
+				// Applying the resource with an invalid timestamp format should fail.
+				Config: testAccLeapfrogResourceWithTimestampFormat("initial", `"iso"`),
+				ExpectError: regexp.MustCompile("expected timestamp_format to be one of"),
+			},
+			{
+				// Re-applying the resource with a changed timestamp format should only reformat the timestamps.
+				PreConfig: testAccFixTime("2021-01-01T00:00:00.3Z"),
+				Config: testAccLeapfrogResourceWithTimestampFormat("initial", `"unix_ms"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "1609459200123"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp", "1609459200123"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger within the same second should update the beta timestamp.
+				PreConfig: testAccFixTime("2021-01-01T00:00:00.5Z"),
+				Config: testAccLeapfrogResourceWithTimestampFormat("change-1", `"unix_ms"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "1609459200123"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp", "1609459200500"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp_unix", "1609459200"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger and timestamp format should reformat the alpha timestamp
+				// and update the beta timestamp.
+				PreConfig: testAccFixTime("2021-01-01T00:00:01.5Z"),
+				Config: testAccLeapfrogResourceWithTimestampFormat("change-2", `"rfc3339"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha", "true"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2021-01-01T00:00:01Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "beta_timestamp", "2021-01-01T00:00:00Z"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp_unix", "1609459201"),
+				),
+			},
+			{
+				// Re-applying the resource without a timestamp format should keep the last timestamp format.
+				PreConfig: testAccFixTime("2021-01-01T00:00:02Z"),
+				Config: testAccLeapfrogResourceWithTimestampFormat("change-2", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "timestamp_format", "rfc3339"),
+					resource.TestCheckResourceAttr("toggles_leapfrog.test", "alpha_timestamp", "2021-01-01T00:00:01Z"),
+				),
+			},
+		},
+	})
+}
+
 func testAccLeapfrogResource (trigger string) string {
 	return fmt.Sprintf(`
 resource "toggles_leapfrog" "test" {
@@ -66,3 +609,90 @@ resource "toggles_leapfrog" "test" {
 }
 `, trigger)
 }
+
+
+// testAccLeapfrogResourceWithUnknownTrigger derives the trigger from the timestamps of another leapfrog, which are
+// unknown during plan whenever that leapfrog is created or toggled.
+func testAccLeapfrogResourceWithUnknownTrigger (trigger string) string {
+	return fmt.Sprintf(`
+resource "toggles_leapfrog" "trigger" {
+  trigger = "%s"
+}
+
+resource "toggles_leapfrog" "test" {
+  trigger = "${toggles_leapfrog.trigger.alpha_timestamp}${toggles_leapfrog.trigger.beta_timestamp}"
+}
+`, trigger)
+}
+
+func testAccLeapfrogResourceWithTriggers (triggers string) string {
+	return fmt.Sprintf(`
+resource "toggles_leapfrog" "test" {
+  triggers = { %s }
+}
+`, triggers)
+}
+
+func testAccLeapfrogResourceWithTriggerMode (mode, trigger string) string {
+	return fmt.Sprintf(`
+resource "toggles_leapfrog" "test" {
+  trigger_mode = "%s"
+  trigger = "%s"
+}
+`, mode, trigger)
+}
+
+func testAccLeapfrogResourceWithValues (trigger, alphaValue, betaValue string) string {
+	return fmt.Sprintf(`
+resource "toggles_leapfrog" "test" {
+  trigger = "%s"
+  alpha_value = "%s"
+  beta_value = "%s"
+}
+`, trigger, alphaValue, betaValue)
+}
+
+// testAccLeapfrogResourceWithUnknownValues derives the alpha value from the timestamps of another leapfrog, which are
+// unknown during plan when its trigger changes.
+func testAccLeapfrogResourceWithUnknownValues (valuesTrigger, trigger string) string {
+	return fmt.Sprintf(`
+resource "toggles_leapfrog" "values" {
+  trigger = "%s"
+}
+
+resource "toggles_leapfrog" "test" {
+  trigger = "%s"
+  alpha_value = toggles_leapfrog.values.beta ? toggles_leapfrog.values.beta_timestamp : toggles_leapfrog.values.alpha_timestamp
+  beta_value = "standby"
+}
+`, valuesTrigger, trigger)
+}
+
+func testAccLeapfrogResourceWithForceActive (trigger, forceActive string) string {
+	return fmt.Sprintf(`
+resource "toggles_leapfrog" "test" {
+  trigger = "%s"
+  force_active = %s
+}
+`, trigger, forceActive)
+}
+
+
+func testAccLeapfrogResourceWithRotationInterval (trigger, rotationInterval string) string {
+	return fmt.Sprintf(`
+resource "toggles_leapfrog" "test" {
+  trigger = "%s"
+  rotation_interval = "%s"
+}
+`, trigger, rotationInterval)
+}
+
+
+func testAccLeapfrogResourceWithTimestampFormat (trigger, timestampFormat string) string {
+	return fmt.Sprintf(`
+resource "toggles_leapfrog" "test" {
+  trigger = "%s"
+  timestamp_format = %s
+}
+`, trigger, timestampFormat)
+}
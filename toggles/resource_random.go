@@ -0,0 +1,318 @@
+package toggles
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strconv"
+)
+
+func resourceRandom() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRandomCreate,
+		ReadContext:   resourceRandomRead,
+		UpdateContext: resourceRandomUpdate,
+		DeleteContext: resourceRandomDelete,
+		CustomizeDiff: customizeDiffRandom,
+		Schema: map[string]*schema.Schema {
+			"trigger": {
+				Type: schema.TypeString,
+				Description: "An arbitrary string value that, when changed, toggles the output.",
+				Optional: true,
+			},
+			"triggers": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A map of arbitrary string values that, when any of them changes, toggles the output.",
+				Optional: true,
+			},
+			"trigger_mode": {
+				Type: schema.TypeString,
+				Description: "Determines when the output is toggled: on_change of the trigger or triggers, always or never. When not set, the output is toggled on each apply if both the trigger and triggers are empty, and on changes otherwise.",
+				Optional: true,
+				ValidateFunc: validation.StringInSlice(triggerModes, false),
+			},
+			"last_trigger_keys": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The sorted keys of the triggers that were added, removed or changed on the last toggle.",
+				Computed: true,
+			},
+			"n": {
+				Type: schema.TypeInt,
+				Description: "The number of outputs. Should be between 2 and 256. Defaults to 2.",
+				Optional: true,
+				Default: 2,
+				ValidateFunc: validation.IntBetween(2, 256),
+			},
+			"seed": {
+				Type: schema.TypeString,
+				Description: "An arbitrary string value that, together with the toggle count, determines the selected outputs.",
+				Optional: true,
+			},
+			"exclude_active": {
+				Type: schema.TypeBool,
+				Description: "Whether to forbid selecting the active output again on a toggle. Defaults to false.",
+				Optional: true,
+				Default: false,
+			},
+			"outputs": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeBool,
+				},
+				Description: "A list of n boolean outputs.",
+				Computed: true,
+			},
+			"active_output": {
+				Type: schema.TypeInt,
+				Description: "The 0-index based number of the active output.",
+				Computed: true,
+			},
+			"counters": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+				Description: "A list of counters denoting the number of times the corresponding output was selected.",
+				Computed: true,
+			},
+			"toggle_count": {
+				Type: schema.TypeInt,
+				Description: "The number of times the output was toggled, which keys the next selection.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+// randomState holds the attributes of a random toggle that change when it toggles. It extends the state of a rotary
+// with the toggle count that keys the selection.
+type randomState struct {
+	rotaryState
+	ToggleCount int
+}
+
+// randomOutput returns the output of n outputs that is selected by a deterministic pseudo-random stream, keyed by the
+// seed and the toggle count. When exclude is one of the outputs, it is never selected.
+func randomOutput(seed string, toggleCount int, n int, exclude int) int {
+	sum := sha256.Sum256([]byte(seed + ":" + strconv.Itoa(toggleCount)))
+	random := binary.BigEndian.Uint64(sum[:8])
+
+	if exclude < 0 || exclude >= n {
+		return int(random % uint64(n))
+	}
+
+	output := int(random % uint64(n-1))
+	if output >= exclude {
+		output += 1
+	}
+
+	return output
+}
+
+// newRandomState returns the initial state of a random toggle with n outputs, which has the first selected output
+// active.
+func newRandomState(n int, seed string) randomState {
+	return randomState{
+		rotaryState: newRotaryState(n, randomOutput(seed, 0, n, -1)),
+		ToggleCount: 0,
+	}
+}
+
+// next returns the state of the random toggle after resizing it to n outputs, and toggling it if toggle is true.
+//
+// Toggling increments the toggle count and selects the next active output, which may be the active output again
+// unless excludeActive is true. Resizing keeps the counters of the remaining outputs like a rotary. If the active output
+// is dropped, the toggle is toggled to select a new active output.
+func (s randomState) next(n int, seed string, excludeActive bool, toggle bool) randomState {
+	state := s.resize(n)
+
+	exclude := -1
+	if excludeActive {
+		exclude = s.ActiveOutput
+	}
+
+	if s.ActiveOutput >= n {
+		toggle = true
+	}
+
+	if !toggle {
+		return randomState{
+			rotaryState: state,
+			ToggleCount: s.ToggleCount,
+		}
+	}
+
+	toggleCount := s.ToggleCount + 1
+	state.ActiveOutput = randomOutput(seed, toggleCount, n, exclude)
+	state.Counters[state.ActiveOutput] += 1
+
+	return randomState{
+		rotaryState: state,
+		ToggleCount: toggleCount,
+	}
+}
+
+// oldRandomState reads the state of the random toggle before the current change.
+// Both schema.ResourceData and schema.ResourceDiff can be used to read the old state.
+func oldRandomState(d resourceGetter) randomState {
+	toggleCount, _ := d.GetChange("toggle_count")
+
+	return randomState{
+		rotaryState: oldRotaryState(d),
+		ToggleCount: toggleCount.(int),
+	}
+}
+
+// nextRandomState returns the state of the random toggle after the current change, and whether it was toggled by the
+// triggers.
+func nextRandomState(d resourceGetter, meta *providerMeta) (randomState, bool) {
+	toggle := shouldToggle(d, meta)
+	state := oldRandomState(d).next(d.Get("n").(int), d.Get("seed").(string), d.Get("exclude_active").(bool), toggle)
+
+	return state, toggle
+}
+
+// setRandomState sets all attributes derived from the state of the random toggle using set, which is either SetNew of
+// schema.ResourceDiff or Set of schema.ResourceData.
+func setRandomState(set func(string, interface{}) error, s randomState) error {
+	if err := setRotaryState(set, s.rotaryState); err != nil {
+		return err
+	}
+
+	if err := set("toggle_count", s.ToggleCount); err != nil {
+		return fmt.Errorf("could not set toggle_count: %+v", err)
+	}
+
+	return nil
+}
+
+// randomConfigKnown returns whether n, the seed and exclude_active are known during plan.
+func randomConfigKnown(d *schema.ResourceDiff) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		return d.NewValueKnown("n") && d.NewValueKnown("seed") && d.NewValueKnown("exclude_active")
+	}
+
+	return config.GetAttr("n").IsKnown() && config.GetAttr("seed").IsKnown() && config.GetAttr("exclude_active").IsKnown()
+}
+
+// customizeDiffRandom ensures that we show changes in the diff phase.
+// As all attributes are set during the diff-phase it functions as both the create and update function.
+// When n, the seed, exclude_active or the trigger are unknown during plan, the outputs are marked as computed and set
+// during apply instead.
+func customizeDiffRandom(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	meta := getProviderMeta(m)
+
+	if d.Id() != "" {
+		if err := clearLastTriggerKeys(d); err != nil {
+			return err
+		}
+	}
+
+	if !randomConfigKnown(d) || (d.Id() != "" && !triggersKnown(d, meta)) {
+		for _, key := range []string{"outputs", "active_output", "counters", "toggle_count", "last_trigger_keys"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return fmt.Errorf("could not mark %s as new computed: %+v", key, err)
+			}
+		}
+
+		return nil
+	}
+
+	// New resource: set the initial state.
+	if d.Id() == "" {
+		if err := d.SetNew("last_trigger_keys", []interface{}{}); err != nil {
+			return fmt.Errorf("could not set last_trigger_keys: %+v", err)
+		}
+
+		return setRandomState(d.SetNew, newRandomState(d.Get("n").(int), d.Get("seed").(string)))
+	}
+
+	old := oldRandomState(d)
+	state, toggled := nextRandomState(d, meta)
+	if len(old.Counters) == len(state.Counters) && state.ToggleCount == old.ToggleCount {
+		return nil
+	}
+
+	if toggled {
+		if err := setLastTriggerKeys(d.SetNew, d); err != nil {
+			return err
+		}
+	}
+
+	return setRandomState(d.SetNew, state)
+}
+
+// resourceRandomCreate ensures the resource's id is set.
+// The initial attribute values are set in customizeDiffRandom, unless n or the seed were unknown during plan.
+func resourceRandomCreate(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !d.GetRawPlan().GetAttr("active_output").IsKnown() {
+		if err := setRandomState(d.Set, newRandomState(d.Get("n").(int), d.Get("seed").(string))); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := d.Set("last_trigger_keys", []interface{}{}); err != nil {
+			return diag.Errorf("could not set last_trigger_keys: %+v", err)
+		}
+	}
+
+	id, err := newToggleID()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id)
+
+	return diags
+}
+
+// resourceRandomRead is a noop as all attributes are internal.
+func resourceRandomRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	return diags
+}
+
+// resourceRandomUpdate sets the outputs when n, the seed, exclude_active or the trigger were unknown during plan.
+func resourceRandomUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if d.GetRawPlan().GetAttr("active_output").IsKnown() {
+		return diags
+	}
+
+	state, toggled := nextRandomState(d, getProviderMeta(m))
+	if err := setRandomState(d.Set, state); err != nil {
+		return diag.FromErr(err)
+	}
+
+	lastTriggerKeys, _ := d.GetChange("last_trigger_keys")
+	if toggled {
+		lastTriggerKeys = changedTriggerKeys(d)
+	}
+
+	if err := d.Set("last_trigger_keys", lastTriggerKeys); err != nil {
+		return diag.Errorf("could not set last_trigger_keys: %+v", err)
+	}
+
+	return diags
+}
+
+// resourceRandomDelete is a noop, as no external resource are being managed.
+func resourceRandomDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	return diags
+}
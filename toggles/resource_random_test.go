@@ -0,0 +1,126 @@
+package toggles
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"strconv"
+	"testing"
+)
+
+func TestRandomOutput(t *testing.T) {
+	for toggleCount := 0; toggleCount < 100; toggleCount++ {
+		output := randomOutput("seed", toggleCount, 5, -1)
+		if output < 0 || output >= 5 {
+			t.Fatalf("expected an output between 0 and 4, got %d", output)
+		}
+
+		// The same seed and toggle count should always select the same output.
+		if again := randomOutput("seed", toggleCount, 5, -1); again != output {
+			t.Fatalf("expected output %d to be selected again, got %d", output, again)
+		}
+
+		// An excluded output should never be selected.
+		if excluded := randomOutput("seed", toggleCount, 5, output); excluded == output || excluded < 0 || excluded >= 5 {
+			t.Fatalf("expected an output between 0 and 4 other than %d, got %d", output, excluded)
+		}
+	}
+}
+
+func TestRandomOutput_distribution(t *testing.T) {
+	counts := make([]int, 4)
+	for toggleCount := 0; toggleCount < 4000; toggleCount++ {
+		counts[randomOutput("seed", toggleCount, 4, -1)] += 1
+	}
+
+	for output, count := range counts {
+		if count < 800 || count > 1200 {
+			t.Fatalf("expected output %d to be selected about 1000 times, got %d (%v)", output, count, counts)
+		}
+	}
+}
+
+func TestAccRandom(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should select the first output of the seeded stream.
+				Config: testAccRandomResource("initial", "seed", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_random.test", "toggle_count", "0"),
+					resource.TestCheckResourceAttr("toggles_random.test", "outputs.#", "4"),
+					resource.TestCheckResourceAttr("toggles_random.test", "active_output", strconv.Itoa(randomOutput("seed", 0, 4, -1))),
+				),
+			},
+			{
+				// Re-applying the resource with an un-changed trigger should not toggle.
+				Config: testAccRandomResource("initial", "seed", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_random.test", "toggle_count", "0"),
+					resource.TestCheckResourceAttr("toggles_random.test", "active_output", strconv.Itoa(randomOutput("seed", 0, 4, -1))),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should select the next output of the seeded stream.
+				Config: testAccRandomResource("active-1", "seed", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_random.test", "toggle_count", "1"),
+					resource.TestCheckResourceAttr("toggles_random.test", "active_output", strconv.Itoa(randomOutput("seed", 1, 4, -1))),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value and exclude_active should select another output.
+				Config: testAccRandomResource("active-2", "seed", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_random.test", "toggle_count", "2"),
+					resource.TestCheckResourceAttr("toggles_random.test", "active_output", strconv.Itoa(randomOutput("seed", 2, 4, randomOutput("seed", 1, 4, -1)))),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRandom_sequence(t *testing.T) {
+	steps := []resource.TestStep{}
+
+	active := -1
+	for toggleCount := 0; toggleCount < 5; toggleCount++ {
+		active = randomOutput("other", toggleCount, 3, active)
+		steps = append(steps, resource.TestStep{
+			// Each toggle with exclude_active should select another output, following the seeded stream.
+			Config: testAccRandomResourceWithN(fmt.Sprintf("active-%d", toggleCount), "other", 3),
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("toggles_random.test", "toggle_count", strconv.Itoa(toggleCount)),
+				resource.TestCheckResourceAttr("toggles_random.test", "active_output", strconv.Itoa(active)),
+				resource.TestCheckResourceAttr("toggles_random.test", fmt.Sprintf("outputs.%d", active), "true"),
+			),
+		})
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: steps,
+	})
+}
+
+func testAccRandomResource (trigger string, seed string, excludeActive bool) string {
+	return fmt.Sprintf(`
+resource "toggles_random" "test" {
+  trigger = "%s"
+  seed = "%s"
+  n = 4
+  exclude_active = %t
+}
+`, trigger, seed, excludeActive)
+}
+
+func testAccRandomResourceWithN (trigger string, seed string, n int) string {
+	return fmt.Sprintf(`
+resource "toggles_random" "test" {
+  trigger = "%s"
+  seed = "%s"
+  n = %d
+  exclude_active = true
+}
+`, trigger, seed, n)
+}
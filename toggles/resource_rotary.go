@@ -6,6 +6,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strconv"
+	"strings"
 )
 
 func resourceRotary() *schema.Resource {
@@ -15,18 +17,89 @@ func resourceRotary() *schema.Resource {
 		UpdateContext: resourceRotaryUpdate,
 		DeleteContext: resourceRotaryDelete,
 		CustomizeDiff: customizeDiffRotary,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRotaryImport,
+		},
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceRotaryV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceRotaryStateUpgradeV0,
+				Version: 0,
+			},
+			{
+				Type:    resourceRotaryV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceRotaryStateUpgradeV1,
+				Version: 1,
+			},
+		},
 		Schema: map[string]*schema.Schema {
 			"trigger": {
 				Type: schema.TypeString,
 				Description: "An arbitrary string value that, when changed, toggles the output.",
 				Optional: true,
 			},
+			"triggers": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A map of arbitrary string values that, when any of them changes, toggles the output.",
+				Optional: true,
+			},
+			"trigger_mode": {
+				Type: schema.TypeString,
+				Description: "Determines when the output is toggled: on_change of the trigger or triggers, always or never. When not set, the output is toggled on each apply if both the trigger and triggers are empty, and on changes otherwise.",
+				Optional: true,
+				ValidateFunc: validation.StringInSlice(triggerModes, false),
+			},
+			"last_trigger_keys": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The sorted keys of the triggers that were added, removed or changed on the last toggle.",
+				Computed: true,
+			},
 			"n": {
 				Type: schema.TypeInt,
-				Description: "The number of outputs. Should be between 2 and 256",
+				Description: "The number of outputs. Should be between 2 and 256. Defaults to 2, or the number of values when they are set. Conflicts with values.",
 				Optional: true,
-				ForceNew: true,
+				Computed: true,
 				ValidateFunc: validation.IntBetween(2, 256),
+				ConflictsWith: []string{"values"},
+			},
+			"values": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A list of named outputs to rotate between. The number of values defines n.",
+				Optional: true,
+				MinItems: 2,
+				MaxItems: 256,
+				ConflictsWith: []string{"n"},
+			},
+			"step": {
+				Type: schema.TypeInt,
+				Description: "The number of outputs to advance on each rotation. Negative values rotate backwards. Defaults to 1. Should not be a multiple of n.",
+				Optional: true,
+				Default: 1,
+			},
+			"pinned_output": {
+				Type: schema.TypeInt,
+				Description: "The 0-index based number of an output to hold active, regardless of the triggers. Normal rotation resumes from the pinned output when unset.",
+				Optional: true,
+				ValidateFunc: validation.IntBetween(0, 255),
+			},
+			"disabled_outputs": {
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+					ValidateFunc: validation.IntBetween(0, 255),
+				},
+				Description: "A set of 0-index based numbers of outputs that are skipped when rotating. An active output that becomes disabled is rotated off.",
+				Optional: true,
 			},
 			"outputs": {
 				Type: schema.TypeList,
@@ -50,84 +123,622 @@ func resourceRotary() *schema.Resource {
 				Description: "A list of counters denoting the number of times the corresponding output was set to true.",
 				Computed: true,
 			},
+			"active_value": {
+				Type: schema.TypeString,
+				Description: "The value of the active output. Empty when values is not set.",
+				Computed: true,
+			},
+			"previous_value": {
+				Type: schema.TypeString,
+				Description: "The value of the closest enabled output step outputs before the active output. Empty when values is not set.",
+				Computed: true,
+			},
+			"next_value": {
+				Type: schema.TypeString,
+				Description: "The value of the output that becomes active on the next rotation. Empty when values is not set.",
+				Computed: true,
+			},
+			"timestamp_format": {
+				Type: schema.TypeString,
+				Description: "The format of the timestamps: rfc3339, rfc3339nano, unix or unix_ms. Defaults to rfc3339nano.",
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice(timestampFormats, false),
+			},
+			"timestamps": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A list of timestamps in the timestamp_format denoting the last time the corresponding output was set to true.",
+				Computed: true,
+			},
+			"timestamps_unix": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+				Description: "The timestamps as the number of seconds since the unix epoch.",
+				Computed: true,
+			},
 		},
 	}
 }
 
+// rotaryState holds the attributes of a rotary that change when it rotates. The outputs are derived from the active
+// output and the number of counters.
+type rotaryState struct {
+	ActiveOutput int
+	Counters     []int
+}
+
+// newRotaryState returns the initial state of a rotary with n outputs, which has the given output active.
+func newRotaryState(n int, activeOutput int) rotaryState {
+	counters := make([]int, n, n)
+	counters[activeOutput] = 1
+
+	return rotaryState{
+		ActiveOutput: activeOutput,
+		Counters:     counters,
+	}
+}
+
+// resize returns the state of the rotary with n outputs, which keeps the counters of the remaining outputs. When
+// growing, the new outputs are appended as inactive outputs. When shrinking, the tail outputs are dropped, which may
+// include the active output.
+func (s rotaryState) resize(n int) rotaryState {
+	counters := make([]int, n, n)
+	copy(counters, s.Counters)
+
+	return rotaryState{
+		ActiveOutput: s.ActiveOutput,
+		Counters:     counters,
+	}
+}
+
+// next returns the state of the rotary after resizing it to c.N outputs, and rotating it by c.Step outputs if rotate is
+// true. Disabled outputs are skipped. When no other enabled output can be reached, the active output stays active.
+//
+// Resizing keeps the counters of the remaining outputs. When growing, the new outputs are appended as inactive
+// outputs. When shrinking, the tail outputs are dropped. If the active output is dropped, the first enabled output
+// becomes active, as that is the output the rotation would wrap around to. If the active output is disabled, the rotary
+// rotates off it. Relocating the active output counts as a rotation.
+func (s rotaryState) next(c rotaryConfig, rotate bool) (rotaryState, error) {
+	n := c.N
+	state := s.resize(n)
+
+	activeOutput := s.ActiveOutput
+	if activeOutput >= n {
+		activeOutput = c.enabledOutput(0, 1)
+	} else if rotate || c.Disabled[activeOutput] {
+		activeOutput = c.enabledOutput(rotaryOffset(activeOutput, c.Step, n), c.Step)
+	}
+
+	if activeOutput < 0 {
+		return rotaryState{}, fmt.Errorf("no enabled output can be reached from output %d with step %d", s.ActiveOutput, c.Step)
+	}
+
+	if activeOutput != s.ActiveOutput {
+		state.Counters[activeOutput] += 1
+	}
+
+	state.ActiveOutput = activeOutput
+
+	return state, nil
+}
+
+// pin returns the state of the rotary after resizing it to n outputs, and holding the given output active. The counter
+// of the pinned output is only incremented when it becomes active.
+func (s rotaryState) pin(n int, output int) rotaryState {
+	state := s.resize(n)
+
+	if output != s.ActiveOutput {
+		state.Counters[output] += 1
+	}
+
+	state.ActiveOutput = output
+
+	return state
+}
+
+// rotaryOffset returns the output that is step outputs away from output in a rotary with n outputs. The step may be
+// negative.
+func rotaryOffset(output int, step int, n int) int {
+	return ((output+step)%n + n) % n
+}
+
+// rotaryConfig holds the configuration that determines how a rotary rotates.
+type rotaryConfig struct {
+	N        int
+	Step     int
+	Disabled map[int]bool
+}
+
+// enabledOutput returns the first output that is not disabled, starting at start and advancing by step outputs. It
+// returns -1 if no enabled output can be reached.
+func (c rotaryConfig) enabledOutput(start int, step int) int {
+	for i := 0; i < c.N; i++ {
+		output := rotaryOffset(start, i*step, c.N)
+		if !c.Disabled[output] {
+			return output
+		}
+	}
+
+	return -1
+}
+
+// newRotaryConfig returns the configuration of a rotary with n outputs that advances by step outputs on each rotation
+// and skips the disabled outputs, and returns an error when they are inconsistent.
+func newRotaryConfig(n int, step int, disabledOutputs []interface{}) (rotaryConfig, error) {
+	if n < 2 {
+		return rotaryConfig{}, fmt.Errorf("n (%d) should be between 2 and 256", n)
+	}
+
+	if step%n == 0 {
+		return rotaryConfig{}, fmt.Errorf("step (%d) should not be a multiple of n (%d)", step, n)
+	}
+
+	disabled := map[int]bool{}
+	for _, output := range disabledOutputs {
+		if output.(int) >= n {
+			return rotaryConfig{}, fmt.Errorf("disabled output (%d) should be between 0 and %d", output, n-1)
+		}
+
+		disabled[output.(int)] = true
+	}
+
+	if len(disabled) == n {
+		return rotaryConfig{}, fmt.Errorf("all %d outputs are disabled, at least one output should be enabled", n)
+	}
+
+	return rotaryConfig{
+		N:        n,
+		Step:     step,
+		Disabled: disabled,
+	}, nil
+}
+
+// readRotaryConfig reads the number of outputs, the step, the disabled outputs and the pinned output of a rotary, and
+// returns an error when they are inconsistent.
+func readRotaryConfig(d resourceGetter) (rotaryConfig, error) {
+	c, err := newRotaryConfig(rotaryN(d), d.Get("step").(int), d.Get("disabled_outputs").(*schema.Set).List())
+	if err != nil {
+		return rotaryConfig{}, err
+	}
+
+	if output, ok := rotaryPinnedOutput(d); ok {
+		if output >= c.N {
+			return rotaryConfig{}, fmt.Errorf("pinned_output (%d) should be between 0 and %d", output, c.N-1)
+		}
+
+		if c.Disabled[output] {
+			return rotaryConfig{}, fmt.Errorf("pinned_output (%d) should not be disabled", output)
+		}
+	}
+
+	return c, nil
+}
+
+// outputs returns the list of boolean outputs, in which only the active output is true.
+func (s rotaryState) outputs() []interface{} {
+	outputs := make([]interface{}, len(s.Counters), len(s.Counters))
+	for i := range outputs {
+		outputs[i] = i == s.ActiveOutput
+	}
+
+	return outputs
+}
+
+// oldRotaryState reads the state of the rotary before the current change.
+// Both schema.ResourceData and schema.ResourceDiff can be used to read the old state.
+func oldRotaryState(d resourceGetter) rotaryState {
+	activeOutput, _ := d.GetChange("active_output")
+	counters, _ := d.GetChange("counters")
+
+	return toRotaryState(activeOutput, counters)
+}
+
+// currentRotaryState reads the state of the rotary including the current change.
+func currentRotaryState(d resourceGetter) rotaryState {
+	return toRotaryState(d.Get("active_output"), d.Get("counters"))
+}
+
+// toRotaryState converts the raw active_output and counters attribute values to a rotaryState.
+func toRotaryState(activeOutput interface{}, rawCounters interface{}) rotaryState {
+	return rotaryState{
+		ActiveOutput: activeOutput.(int),
+		Counters:     toInts(rawCounters.([]interface{})),
+	}
+}
+
+// setRotaryState sets all attributes derived from the state of the rotary using set, which is either SetNew of
+// schema.ResourceDiff or Set of schema.ResourceData.
+func setRotaryState(set func(string, interface{}) error, s rotaryState) error {
+	if err := set("outputs", s.outputs()); err != nil {
+		return fmt.Errorf("could not set outputs: %+v", err)
+	}
+
+	if err := set("active_output", s.ActiveOutput); err != nil {
+		return fmt.Errorf("could not set active_output: %+v", err)
+	}
+
+	if err := set("counters", fromInts(s.Counters)); err != nil {
+		return fmt.Errorf("could not set counters: %+v", err)
+	}
+
+	return nil
+}
+
+// toInts converts a raw list attribute value of integers to a slice of integers.
+func toInts(raw []interface{}) []int {
+	ints := make([]int, len(raw), len(raw))
+	for i, value := range raw {
+		ints[i] = value.(int)
+	}
+
+	return ints
+}
+
+// fromInts converts a slice of integers to a raw list attribute value.
+func fromInts(ints []int) []interface{} {
+	raw := make([]interface{}, len(ints), len(ints))
+	for i, value := range ints {
+		raw[i] = value
+	}
+
+	return raw
+}
+
+// rotaryDefaultN is the number of outputs of a rotary without n or values.
+const rotaryDefaultN = 2
+
+// rotaryN returns the number of outputs of a rotary, which is the number of values if they are set. As n is also
+// computed, the raw configuration is used to tell an unset n apart from the last n.
+func rotaryN(d resourceGetter) int {
+	if values := d.Get("values").([]interface{}); len(values) > 0 {
+		return len(values)
+	}
+
+	if config := d.GetRawConfig(); !config.IsNull() && config.GetAttr("n").IsNull() {
+		return rotaryDefaultN
+	}
+
+	return d.Get("n").(int)
+}
+
+// setNewRotaryN sets n to the number of outputs, or marks it as new computed when n or the values are unknown during
+// plan. SetNew of n clears the diff of every attribute that starts with n, including next_value, so it should be called
+// before the values are set.
+func setNewRotaryN(d *schema.ResourceDiff) error {
+	config := d.GetRawConfig()
+	if !config.IsNull() && (!config.GetAttr("n").IsKnown() || !config.GetAttr("values").IsWhollyKnown()) {
+		if err := d.SetNewComputed("n"); err != nil {
+			return fmt.Errorf("could not mark n as new computed: %+v", err)
+		}
+
+		return nil
+	}
+
+	if err := d.SetNew("n", rotaryN(d)); err != nil {
+		return fmt.Errorf("could not set n: %+v", err)
+	}
+
+	return nil
+}
+
+// rotaryConfigKnown returns whether the number of outputs, the values if they are set, the step, the disabled outputs
+// and the pinned output are known during plan.
+func rotaryConfigKnown(d *schema.ResourceDiff) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		return d.NewValueKnown("n") && d.NewValueKnown("values") && d.NewValueKnown("step") &&
+			d.NewValueKnown("disabled_outputs") && d.NewValueKnown("pinned_output")
+	}
+
+	return config.GetAttr("n").IsKnown() && config.GetAttr("values").IsWhollyKnown() && config.GetAttr("step").IsKnown() &&
+		config.GetAttr("disabled_outputs").IsWhollyKnown() && config.GetAttr("pinned_output").IsKnown()
+}
+
+// rotaryPinnedOutput returns the pinned output, and whether an output is pinned. As the 0th output can be pinned, the
+// raw configuration is used to tell an unset pinned output apart from 0.
+func rotaryPinnedOutput(d resourceGetter) (int, bool) {
+	config := d.GetRawConfig()
+	if config.IsNull() || config.GetAttr("pinned_output").IsNull() {
+		return 0, false
+	}
+
+	return d.Get("pinned_output").(int), true
+}
+
+// nextRotaryState returns the state of the rotary after resizing it to c.N outputs, and whether it was rotated by the
+// triggers. A pinned output is held active regardless of the triggers, unless the provider is frozen. A frozen provider
+// holds the active output, even when it is disabled, unless it is dropped by resizing the rotary.
+func nextRotaryState(d resourceGetter, c rotaryConfig, meta *providerMeta) (rotaryState, bool, error) {
+	old := oldRotaryState(d)
+	if output, ok := rotaryPinnedOutput(d); ok && !meta.Frozen {
+		return old.pin(c.N, output), false, nil
+	}
+
+	if meta.Frozen && old.ActiveOutput < c.N {
+		return old.pin(c.N, old.ActiveOutput), false, nil
+	}
+
+	rotate := shouldToggle(d, meta)
+	state, err := old.next(c, rotate)
+
+	return state, rotate, err
+}
+
+// suppressedRotaryToggle describes the change that would have rotated the rotary from the state, after resizing it to c.N
+// outputs, if the provider was not frozen. It is empty when the provider is not frozen or the rotary would not rotate.
+func suppressedRotaryToggle(d resourceGetter, c rotaryConfig, state rotaryState, meta *providerMeta) (string, error) {
+	if !meta.Frozen {
+		return "", nil
+	}
+
+	thawed := meta.thawed()
+	thawedState, _, err := nextRotaryState(d, c, thawed)
+	if err != nil || thawedState.ActiveOutput == state.ActiveOutput {
+		return "", err
+	}
+
+	if output, ok := rotaryPinnedOutput(d); ok {
+		return fmt.Sprintf("pinned_output %d", output), nil
+	}
+
+	if old := oldRotaryState(d); c.Disabled[old.ActiveOutput] {
+		return fmt.Sprintf("disabling the active output %d", old.ActiveOutput), nil
+	}
+
+	return describeTrigger(d, triggerMode(d, thawed)), nil
+}
+
+// initialRotaryState returns the state of a new rotary with c.N outputs, which has the pinned output or the first
+// enabled output active.
+func initialRotaryState(d resourceGetter, c rotaryConfig) rotaryState {
+	output, ok := rotaryPinnedOutput(d)
+	if !ok {
+		output = c.enabledOutput(0, 1)
+	}
+
+	return newRotaryState(c.N, output)
+}
+
+// setRotaryValues sets the active, previous and next value using set, which is either SetNew of schema.ResourceDiff or
+// Set of schema.ResourceData. The previous and next value are the closest enabled outputs c.Step outputs away from the
+// active output. The values are empty when no values are configured.
+func setRotaryValues(set func(string, interface{}) error, values []interface{}, c rotaryConfig, s rotaryState) error {
+	n := len(s.Counters)
+
+	var activeValue, previousValue, nextValue string
+	if len(values) == n {
+		activeValue = values[s.ActiveOutput].(string)
+
+		if previous := c.enabledOutput(rotaryOffset(s.ActiveOutput, -c.Step, n), -c.Step); previous >= 0 {
+			previousValue = values[previous].(string)
+		}
+
+		if next := c.enabledOutput(rotaryOffset(s.ActiveOutput, c.Step, n), c.Step); next >= 0 {
+			nextValue = values[next].(string)
+		}
+	}
+
+	if err := set("active_value", activeValue); err != nil {
+		return fmt.Errorf("could not set active_value: %+v", err)
+	}
+
+	if err := set("previous_value", previousValue); err != nil {
+		return fmt.Errorf("could not set previous_value: %+v", err)
+	}
+
+	if err := set("next_value", nextValue); err != nil {
+		return fmt.Errorf("could not set next_value: %+v", err)
+	}
+
+	return nil
+}
+
 // customizeDiffRotary ensures that we show changes in the diff phase.
 // As most attributes are set during the diff-phase it functions as both the create and update function for most things.
-func customizeDiffRotary(ctx context.Context, d *schema.ResourceDiff, i interface{}) error {
-	// New resource: only set outputs and active_output now. The counters are set in resourceRotaryCreate
+// When n, the values, the step, the disabled or pinned output or the trigger are unknown during plan, the outputs are marked as computed and set during apply
+// instead. When the provider is frozen, a suppressed rotation is reported according to the freeze behavior.
+func customizeDiffRotary(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	meta := getProviderMeta(m)
+
+	if d.Id() != "" {
+		if err := clearLastTriggerKeys(d); err != nil {
+			return err
+		}
+	}
+
+	if err := setNewRotaryN(d); err != nil {
+		return err
+	}
+
+	if !rotaryConfigKnown(d) || (d.Id() != "" && !triggersKnown(d, meta)) {
+		for _, key := range []string{"outputs", "active_output", "counters", "timestamps", "timestamps_unix", "active_value", "previous_value", "next_value", "last_trigger_keys"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return fmt.Errorf("could not mark %s as new computed: %+v", key, err)
+			}
+		}
+
+		return nil
+	}
+
+	c, err := readRotaryConfig(d)
+	if err != nil {
+		return err
+	}
+
+	values := d.Get("values").([]interface{})
+
+	// New resource: set the initial state.
 	if d.Id() == "" {
-		n := d.Get("n").(int)
+		if err := d.SetNew("last_trigger_keys", []interface{}{}); err != nil {
+			return fmt.Errorf("could not set last_trigger_keys: %+v", err)
+		}
 
-		outputs := make([]interface{}, n, n)
-		outputs[0] = true
+		if err := setNewDefaultTimestampFormat(d, meta); err != nil {
+			return err
+		}
 
-		if err := d.SetNew("outputs", outputs); err != nil {
-			return fmt.Errorf("could not set outputs: %+v", err)
+		state := initialRotaryState(d, c)
+		if err := setRotaryValues(d.SetNew, values, c, state); err != nil {
+			return err
 		}
 
-		if err := d.SetNew("active_output", 0); err != nil {
-			return fmt.Errorf("could not set active_output: %+v", err)
+		return setRotaryState(d.SetNew, state)
+	}
+
+	old := oldRotaryState(d)
+	state, rotated, err := nextRotaryState(d, c, meta)
+	if err != nil {
+		return err
+	}
+
+	suppressed, err := suppressedRotaryToggle(d, c, state, meta)
+	if err != nil {
+		return err
+	}
+
+	if suppressed != "" {
+		if err := reportSuppressedToggle(meta, suppressedToggleMessage("toggles_rotary", d.Id(), suppressed)); err != nil {
+			return err
 		}
+	}
 
-		counters := make([]interface{}, n, n)
-		counters[0] = 1
-		//for i := 0; i < n; i++ {
-		//	counters[i] = 0
-		//}
+	if len(old.Counters) == c.N && state.ActiveOutput == old.ActiveOutput {
+		// The timestamps are reformatted in resourceRotaryUpdate when the timestamp format changes.
+		if timestampFormatChanged(d) {
+			if err := setNewComputedRotaryTimestamps(d); err != nil {
+				return err
+			}
+		}
 
-		if err := d.SetNew("counters", counters); err != nil {
-			return fmt.Errorf("could not set counters: %+v", err)
+		if d.HasChange("values") || d.HasChange("step") || d.HasChange("disabled_outputs") {
+			return setRotaryValues(d.SetNew, values, c, old)
 		}
 
 		return nil
 	}
 
-	// If the trigger is set, but does not have a change, we shouldn't change anything.
-	// If the trigger is empty, always update.
-	trigger := d.Get("trigger").(string)
-	if trigger != "" && !d.HasChange("trigger")  {
-		return nil
+	if rotated {
+		if err := setLastTriggerKeys(d.SetNew, d); err != nil {
+			return err
+		}
 	}
 
-	n := d.Get("n").(int)
+	// The timestamps of the activated output, or of the added outputs, are set in resourceRotaryUpdate
+	if err := setNewComputedRotaryTimestamps(d); err != nil {
+		return err
+	}
 
-	currentActiveOutput := d.Get("active_output").(int)
-	nextActiveOutput := (currentActiveOutput + 1) % n
+	if err := setRotaryValues(d.SetNew, values, c, state); err != nil {
+		return err
+	}
 
-	outputs := d.Get("outputs").([]interface{})
-	outputs[currentActiveOutput] = false
-	outputs[nextActiveOutput] = true
+	return setRotaryState(d.SetNew, state)
+}
 
-	if err := d.SetNew("outputs", outputs); err != nil {
-		return fmt.Errorf("could not set outputs: %+v", err)
+// setNewComputedRotaryTimestamps marks the timestamps as new computed, they are set in resourceRotaryUpdate.
+func setNewComputedRotaryTimestamps(d *schema.ResourceDiff) error {
+	// SetNewComputed of the timestamps also clears the diff of their unix companion, so it is marked last.
+	for _, key := range []string{"timestamps", "timestamps_unix"} {
+		if err := d.SetNewComputed(key); err != nil {
+			return fmt.Errorf("could not mark %s as new computed: %+v", key, err)
+		}
 	}
 
-	if err := d.SetNew("active_output", nextActiveOutput); err != nil {
-		return fmt.Errorf("could not set active_output: %+v", err)
+	return nil
+}
+
+// setRotaryTimestamps sets the timestamps, which are formatted in the timestamp format, and their unix companions.
+func setRotaryTimestamps(d *schema.ResourceData, timestamps []interface{}, format string) error {
+	unix := make([]interface{}, len(timestamps), len(timestamps))
+	for i, timestamp := range timestamps {
+		var err error
+		if unix[i], err = unixTimestamp(timestamp.(string), format); err != nil {
+			return fmt.Errorf("could not parse timestamp %d: %+v", i, err)
+		}
 	}
 
-	counters := d.Get("counters").([]interface{})
-	count := counters[nextActiveOutput].(int)
-	count += 1
-	counters[nextActiveOutput] = count
+	if err := d.Set("timestamps", timestamps); err != nil {
+		return fmt.Errorf("could not set timestamps: %+v", err)
+	}
 
-	if err := d.SetNew("counters", counters); err != nil {
-		return fmt.Errorf("could not set counters: %+v", err)
+	if err := d.Set("timestamps_unix", unix); err != nil {
+		return fmt.Errorf("could not set timestamps_unix: %+v", err)
 	}
 
 	return nil
 }
 
-// resourceRotaryCreate ensure the resource's id is set
-// The initial attribute values are set in customizeDiffRotary.
+// rotaryTimestamps returns the timestamps of a rotary with n outputs, based on the old timestamps.
+// Added outputs, and all outputs if there are no old timestamps, are initialised with now. The active output is set to
+// now if it was activated.
+func rotaryTimestamps(old []interface{}, n int, activeOutput int, activated bool, now string) []interface{} {
+	timestamps := make([]interface{}, n, n)
+	for i := range timestamps {
+		if i < len(old) && old[i] != "" {
+			timestamps[i] = old[i]
+		} else {
+			timestamps[i] = now
+		}
+	}
+
+	if activated {
+		timestamps[activeOutput] = now
+	}
+
+	return timestamps
+}
+
+// resourceRotaryCreate ensure the resource's id is set and initialises the timestamps.
+// The initial attribute values are set in customizeDiffRotary, unless n, the values, the step, or the disabled or pinned
+// output were unknown during plan.
 func resourceRotaryCreate(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	// Not important
-	d.SetId("toggle")
+	if !d.GetRawPlan().GetAttr("n").IsKnown() {
+		if err := d.Set("n", rotaryN(d)); err != nil {
+			return diag.Errorf("could not set n: %+v", err)
+		}
+	}
+
+	if !d.GetRawPlan().GetAttr("active_output").IsKnown() {
+		c, err := readRotaryConfig(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		state := initialRotaryState(d, c)
+		if err := setRotaryState(d.Set, state); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := setRotaryValues(d.Set, d.Get("values").([]interface{}), c, state); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := d.Set("last_trigger_keys", []interface{}{}); err != nil {
+			return diag.Errorf("could not set last_trigger_keys: %+v", err)
+		}
+	}
+
+	format := d.Get("timestamp_format").(string)
+	now := formatTimestamp(getProviderMeta(m).now(), format)
+	timestamps := rotaryTimestamps(nil, len(d.Get("counters").([]interface{})), 0, false, now)
+
+	if err := setRotaryTimestamps(d, timestamps, format); err != nil {
+		return diag.FromErr(err)
+	}
+
+	id, err := newToggleID()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id)
 
 	return diags
 }
@@ -139,10 +750,91 @@ func resourceRotaryRead(ctx context.Context, d *schema.ResourceData, m interface
 	return diags
 }
 
-// resourceRotaryUpdate is a noop because all the updates happen in customizeDiffRotary
+// resourceRotaryUpdate sets the outputs when n, the values, the step, the disabled or pinned output or the trigger were
+// unknown during plan.
+// It also updates the values and the timestamps of the activated output and of added outputs.
 func resourceRotaryUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics  {
 	var diags diag.Diagnostics
 
+	plan := d.GetRawPlan()
+
+	if !plan.GetAttr("n").IsKnown() {
+		if err := d.Set("n", rotaryN(d)); err != nil {
+			return diag.Errorf("could not set n: %+v", err)
+		}
+	}
+
+	c, err := readRotaryConfig(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	meta := getProviderMeta(m)
+	state, rotated, err := nextRotaryState(d, c, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// CustomizeDiff can only log a warning, so a suppressed toggle is also reported as a diagnostic of the apply.
+	suppressed, err := suppressedRotaryToggle(d, c, state, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if suppressed != "" {
+		diags = append(diags, suppressedToggleDiagnostics(meta, suppressedToggleMessage("toggles_rotary", d.Id(), suppressed))...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	if !plan.GetAttr("active_output").IsKnown() {
+		if err := setRotaryState(d.Set, state); err != nil {
+			return diag.FromErr(err)
+		}
+
+		lastTriggerKeys, _ := d.GetChange("last_trigger_keys")
+		if rotated {
+			lastTriggerKeys = changedTriggerKeys(d)
+		}
+
+		if err := d.Set("last_trigger_keys", lastTriggerKeys); err != nil {
+			return diag.Errorf("could not set last_trigger_keys: %+v", err)
+		}
+	}
+
+	// The values are also unknown when they were not set yet by an older version of the provider.
+	if !plan.GetAttr("active_value").IsKnown() {
+		if err := setRotaryValues(d.Set, d.Get("values").([]interface{}), c, currentRotaryState(d)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if !plan.GetAttr("timestamps").IsKnown() {
+		oldActiveOutput, _ := d.GetChange("active_output")
+		oldTimestamps, _ := d.GetChange("timestamps")
+
+		oldFormat, format := d.GetChange("timestamp_format")
+
+		activeOutput := d.Get("active_output").(int)
+		n := len(d.Get("counters").([]interface{}))
+		now := formatTimestamp(meta.now(), format.(string))
+
+		// The old timestamps are reformatted when the timestamp format changed.
+		old := make([]interface{}, len(oldTimestamps.([]interface{})))
+		for i, timestamp := range oldTimestamps.([]interface{}) {
+			var err error
+			if old[i], err = reformatTimestamp(timestamp.(string), oldFormat.(string), format.(string)); err != nil {
+				return diag.Errorf("could not parse timestamp %d: %+v", i, err)
+			}
+		}
+
+		timestamps := rotaryTimestamps(old, n, activeOutput, activeOutput != oldActiveOutput.(int), now)
+		if err := setRotaryTimestamps(d, timestamps, format.(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return diags
 }
 
@@ -152,3 +844,164 @@ func resourceRotaryDelete(ctx context.Context, d *schema.ResourceData, m interfa
 
 	return diags
 }
+
+// resourceRotaryImport restores the state of a rotary from the import ID, and initialises the timestamps with the time
+// of the import.
+// The ID has the format n=<n>;active=<active_output>;counters=<c0>,<c1>,...[;triggers=<triggers>][;trigger=<trigger>].
+// The triggers are a JSON object. The trigger, if present, must be the last field and may contain any character. The
+// trigger and triggers should equal the configured ones, otherwise the next apply will rotate the output.
+func resourceRotaryImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	fields := map[string]string{}
+
+	var triggers map[string]interface{}
+
+	rest := d.Id()
+	for rest != "" {
+		var field string
+		if strings.HasPrefix(rest, importTriggersPrefix) {
+			if triggers != nil {
+				return nil, fmt.Errorf("duplicate field (triggers) in ID")
+			}
+
+			var err error
+			triggers, rest, err = parseImportTriggers(strings.TrimPrefix(rest, importTriggersPrefix))
+			if err != nil {
+				return nil, err
+			}
+
+			if rest != "" && !strings.HasPrefix(rest, ";") {
+				return nil, fmt.Errorf("unexpected characters (%s) after the triggers, expected ;<key>=<value>", rest)
+			}
+
+			rest = strings.TrimPrefix(rest, ";")
+			continue
+		} else if strings.HasPrefix(rest, "trigger=") {
+			field, rest = rest, ""
+		} else if i := strings.Index(rest, ";"); i >= 0 {
+			field, rest = rest[:i], rest[i+1:]
+		} else {
+			field, rest = rest, ""
+		}
+
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid field (%s) in ID, expected <key>=<value>", field)
+		}
+
+		key, value := kv[0], kv[1]
+		switch key {
+		case "n", "active", "counters", "trigger":
+		default:
+			return nil, fmt.Errorf("unknown field (%s) in ID, expected one of n, active, counters, triggers or trigger", key)
+		}
+
+		if _, ok := fields[key]; ok {
+			return nil, fmt.Errorf("duplicate field (%s) in ID", key)
+		}
+
+		fields[key] = value
+	}
+
+	for _, key := range []string{"n", "active", "counters"} {
+		if _, ok := fields[key]; !ok {
+			return nil, fmt.Errorf("missing field (%s) in ID, expected n=<n>;active=<active_output>;counters=<c0>,<c1>,...[;triggers=<triggers>][;trigger=<trigger>]", key)
+		}
+	}
+
+	n, err := strconv.Atoi(fields["n"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid n (%s), expected an integer: %+v", fields["n"], err)
+	}
+
+	if n < 2 || n > 256 {
+		return nil, fmt.Errorf("invalid n (%d), should be between 2 and 256", n)
+	}
+
+	activeOutput, err := strconv.Atoi(fields["active"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid active output (%s), expected an integer: %+v", fields["active"], err)
+	}
+
+	if activeOutput < 0 || activeOutput >= n {
+		return nil, fmt.Errorf("invalid active output (%d), should be between 0 and %d", activeOutput, n-1)
+	}
+
+	values := strings.Split(fields["counters"], ",")
+	if len(values) != n {
+		return nil, fmt.Errorf("invalid number of counters (%d), expected n (%d) counters", len(values), n)
+	}
+
+	counters := make([]interface{}, n, n)
+	for i, value := range values {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid counter %d (%s), expected an integer: %+v", i, value, err)
+		}
+
+		if count < 0 {
+			return nil, fmt.Errorf("invalid counter %d (%d), should not be negative", i, count)
+		}
+
+		counters[i] = count
+	}
+
+	if counters[activeOutput].(int) == 0 {
+		return nil, fmt.Errorf("invalid counter %d (0) for the active output, should be at least 1", activeOutput)
+	}
+
+	outputs := make([]interface{}, n, n)
+	for i := range outputs {
+		outputs[i] = i == activeOutput
+	}
+
+	if trigger, ok := fields["trigger"]; ok {
+		if err := d.Set("trigger", trigger); err != nil {
+			return nil, fmt.Errorf("could not set trigger: %+v", err)
+		}
+	}
+
+	if triggers != nil {
+		if err := d.Set("triggers", triggers); err != nil {
+			return nil, fmt.Errorf("could not set triggers: %+v", err)
+		}
+	}
+
+	if err := d.Set("n", n); err != nil {
+		return nil, fmt.Errorf("could not set n: %+v", err)
+	}
+
+	if err := d.Set("step", 1); err != nil {
+		return nil, fmt.Errorf("could not set step: %+v", err)
+	}
+
+	if err := d.Set("outputs", outputs); err != nil {
+		return nil, fmt.Errorf("could not set outputs: %+v", err)
+	}
+
+	if err := d.Set("active_output", activeOutput); err != nil {
+		return nil, fmt.Errorf("could not set active_output: %+v", err)
+	}
+
+	if err := d.Set("counters", counters); err != nil {
+		return nil, fmt.Errorf("could not set counters: %+v", err)
+	}
+
+	if err := d.Set("timestamp_format", timestampFormatRFC3339Nano); err != nil {
+		return nil, fmt.Errorf("could not set timestamp_format: %+v", err)
+	}
+
+	// The timestamps are not part of the ID, all outputs are initialised with the time of the import.
+	now := formatTimestamp(getProviderMeta(m).now(), timestampFormatRFC3339Nano)
+	if err := setRotaryTimestamps(d, rotaryTimestamps(nil, n, activeOutput, false, now), timestampFormatRFC3339Nano); err != nil {
+		return nil, err
+	}
+
+	id, err := newToggleID()
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(id)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,186 @@
+package toggles
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceRotaryV0 is the schema of version 0 of the rotary resource.
+func resourceRotaryV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema {
+			"trigger": {
+				Type: schema.TypeString,
+				Optional: true,
+			},
+			"n": {
+				Type: schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
+			"outputs": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeBool,
+				},
+				Computed: true,
+			},
+			"active_output": {
+				Type: schema.TypeInt,
+				Computed: true,
+			},
+			"counters": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceRotaryStateUpgradeV0 replaces the constant "toggle" ID that was used by version 0 with a unique ID, which
+// is derived from the prior state.
+func resourceRotaryStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if rawState["id"] == legacyToggleID {
+		id, err := migratedToggleID(rawState)
+		if err != nil {
+			return nil, err
+		}
+
+		rawState["id"] = id
+	}
+
+	return rawState, nil
+}
+
+// resourceRotaryV1 is the schema of version 1 of the rotary resource.
+func resourceRotaryV1() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema {
+			"trigger": {
+				Type: schema.TypeString,
+				Optional: true,
+			},
+			"triggers": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+			},
+			"trigger_mode": {
+				Type: schema.TypeString,
+				Optional: true,
+			},
+			"last_trigger_keys": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"n": {
+				Type: schema.TypeInt,
+				Optional: true,
+			},
+			"values": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+			},
+			"step": {
+				Type: schema.TypeInt,
+				Optional: true,
+			},
+			"pinned_output": {
+				Type: schema.TypeInt,
+				Optional: true,
+			},
+			"disabled_outputs": {
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+				Optional: true,
+			},
+			"outputs": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeBool,
+				},
+				Computed: true,
+			},
+			"active_output": {
+				Type: schema.TypeInt,
+				Computed: true,
+			},
+			"counters": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+				Computed: true,
+			},
+			"active_value": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"previous_value": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"next_value": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"timestamps": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceRotaryStateUpgradeV1 keeps the RFC3339 timestamps of existing rotaries, as version 2 only defaults to RFC3339
+// timestamps with nanoseconds for new rotaries, and sets their unix timestamps. It also sets the default step of
+// rotaries that were created before the step was added, which are upgraded from version 0 through this version, and
+// initialises the timestamps of rotaries that were created before the timestamps were added with the time of the upgrade.
+func resourceRotaryStateUpgradeV1(_ context.Context, rawState map[string]interface{}, m interface{}) (map[string]interface{}, error) {
+	rawState["timestamp_format"] = timestampFormatRFC3339
+
+	if rawState["step"] == nil {
+		rawState["step"] = 1
+	}
+
+	timestamps, ok := rawState["timestamps"].([]interface{})
+	if !ok {
+		counters, _ := rawState["counters"].([]interface{})
+		activeOutput, _ := rawState["active_output"].(float64)
+
+		now := formatTimestamp(getProviderMeta(m).now(), timestampFormatRFC3339)
+		timestamps = rotaryTimestamps(nil, len(counters), int(activeOutput), false, now)
+		rawState["timestamps"] = timestamps
+	}
+
+	unix := make([]interface{}, len(timestamps), len(timestamps))
+	for i, timestamp := range timestamps {
+		value, _ := timestamp.(string)
+
+		var err error
+		if unix[i], err = unixTimestamp(value, timestampFormatRFC3339); err != nil {
+			return nil, fmt.Errorf("could not parse timestamp %d: %+v", i, err)
+		}
+	}
+
+	rawState["timestamps_unix"] = unix
+
+	return rawState, nil
+}
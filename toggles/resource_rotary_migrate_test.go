@@ -0,0 +1,128 @@
+package toggles
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testResourceRotaryStateDataV0() map[string]interface{} {
+	return map[string]interface{}{
+		"id": "toggle",
+		"trigger": "initial",
+		"n": float64(3),
+		"outputs": []interface{}{false, true, false},
+		"active_output": float64(1),
+		"counters": []interface{}{float64(1), float64(1), float64(0)},
+	}
+}
+
+func TestResourceRotaryStateUpgradeV0(t *testing.T) {
+	expected := testResourceRotaryStateDataV0()
+
+	actual, err := resourceRotaryStateUpgradeV0(context.Background(), testResourceRotaryStateDataV0(), nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	// The constant ID should be replaced by a unique ID
+	if !testAccUUIDRegexp.MatchString(actual["id"].(string)) {
+		t.Fatalf("expected a unique id, got %s", actual["id"])
+	}
+
+	// All other attributes should be left untouched
+	delete(expected, "id")
+	delete(actual, "id")
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("\n\nexpected:\n\n%#v\n\ngot:\n\n%#v\n\n", expected, actual)
+	}
+}
+
+func TestResourceRotaryStateUpgradeV0_deterministicId(t *testing.T) {
+	first, err := resourceRotaryStateUpgradeV0(context.Background(), testResourceRotaryStateDataV0(), nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	second, err := resourceRotaryStateUpgradeV0(context.Background(), testResourceRotaryStateDataV0(), nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	// Upgrading the same state again, as every plan does until the state is stored, should result in the same ID
+	if first["id"] != second["id"] {
+		t.Fatalf("expected the same id, got %s and %s", first["id"], second["id"])
+	}
+
+	rawState := testResourceRotaryStateDataV0()
+	rawState["trigger"] = "other"
+
+	other, err := resourceRotaryStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	// Toggles with a different state should get a different ID
+	if first["id"] == other["id"] {
+		t.Fatalf("expected a different id, got %s for both", first["id"])
+	}
+}
+
+func TestResourceRotaryStateUpgradeV0_uniqueId(t *testing.T) {
+	rawState := testResourceRotaryStateDataV0()
+	rawState["id"] = "2f2a5feb-fa68-c59d-18e9-25ee67d8ac84"
+
+	actual, err := resourceRotaryStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	// An ID that is already unique should be kept
+	if actual["id"] != "2f2a5feb-fa68-c59d-18e9-25ee67d8ac84" {
+		t.Fatalf("expected the id to be kept, got %s", actual["id"])
+	}
+}
+
+func TestResourceRotaryStateUpgradeV1(t *testing.T) {
+	rawState := testResourceRotaryStateDataV0()
+	rawState["timestamps"] = []interface{}{"2021-01-01T00:00:00Z", "2021-01-02T00:00:00Z", "2021-01-01T00:00:00Z"}
+
+	actual, err := resourceRotaryStateUpgradeV1(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	// Existing rotaries should keep their RFC3339 timestamps, get unix timestamps and the default step
+	expected := testResourceRotaryStateDataV0()
+	expected["timestamps"] = []interface{}{"2021-01-01T00:00:00Z", "2021-01-02T00:00:00Z", "2021-01-01T00:00:00Z"}
+	expected["timestamp_format"] = "rfc3339"
+	expected["timestamps_unix"] = []interface{}{1609459200, 1609545600, 1609459200}
+	expected["step"] = 1
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("\n\nexpected:\n\n%#v\n\ngot:\n\n%#v\n\n", expected, actual)
+	}
+}
+
+func TestResourceRotaryStateUpgradeV1_timestamps(t *testing.T) {
+	meta := newProviderMeta()
+	meta.Clock = fixedClock(time.Date(2022, 2, 2, 2, 2, 2, 0, time.UTC))
+
+	actual, err := resourceRotaryStateUpgradeV1(context.Background(), testResourceRotaryStateDataV0(), meta)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	// Rotaries created before the timestamps were added should have them initialised with the time of the upgrade
+	expected := testResourceRotaryStateDataV0()
+	expected["timestamps"] = []interface{}{"2022-02-02T02:02:02Z", "2022-02-02T02:02:02Z", "2022-02-02T02:02:02Z"}
+	expected["timestamp_format"] = "rfc3339"
+	expected["timestamps_unix"] = []interface{}{1643767322, 1643767322, 1643767322}
+	expected["step"] = 1
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("\n\nexpected:\n\n%#v\n\ngot:\n\n%#v\n\n", expected, actual)
+	}
+}
@@ -3,10 +3,13 @@ package toggles
 import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"regexp"
 	"testing"
 )
 
 func TestAccRotary(t *testing.T) {
+	defer testAccRestoreTime()
+
 	n := 4
 
 	resource.Test(t, resource.TestCase{
@@ -15,9 +18,10 @@ func TestAccRotary(t *testing.T) {
 			{
 				// Applying the resource for the first time should set the 0th output to active and initialize all
 				// counters with equal values.
-				PreConfig: sleep,
+				PreConfig: testAccTick,
 				Config: testAccRotaryResource("initial", n),
 				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("toggles_rotary.test", "id", testAccUUIDRegexp),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "true"),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "false"),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "false"),
@@ -27,11 +31,16 @@ func TestAccRotary(t *testing.T) {
 					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamps.#", "4"),
+					testAccValidRFC3339("toggles_rotary.test", "timestamps.0"),
+					resource.TestCheckResourceAttrPair("toggles_rotary.test", "timestamps.0", "toggles_rotary.test", "timestamps.1"),
+					resource.TestCheckResourceAttrPair("toggles_rotary.test", "timestamps.0", "toggles_rotary.test", "timestamps.2"),
+					resource.TestCheckResourceAttrPair("toggles_rotary.test", "timestamps.0", "toggles_rotary.test", "timestamps.3"),
 				),
 			},
 			{
 				// Re-applying the resource with an un-changed trigger value should have the same output.
-				PreConfig: sleep,
+				PreConfig: testAccTick,
 				Config: testAccRotaryResource("initial", n),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "true"),
@@ -48,7 +57,7 @@ func TestAccRotary(t *testing.T) {
 			{
 				// Re-applying the resource with a changed trigger value should mark the old output as inactive, and the
 				// next output as active. It should also increment the counter for the new active output.
-				PreConfig: sleep,
+				PreConfig: testAccTick,
 				Config: testAccRotaryResource("active-1", n),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "false"),
@@ -60,12 +69,13 @@ func TestAccRotary(t *testing.T) {
 					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					testAccTimeAfter("toggles_rotary.test", "timestamps.1", "toggles_rotary.test", "timestamps.0"),
 				),
 			},
 			{
 				// Re-applying the resource with a changed trigger value should mark the old output as inactive, and the
 				// next output as active.
-				PreConfig: sleep,
+				PreConfig: testAccTick,
 				Config: testAccRotaryResource("active-2", n),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "false"),
@@ -77,12 +87,60 @@ func TestAccRotary(t *testing.T) {
 					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "2"),
+					testAccTimeAfter("toggles_rotary.test", "timestamps.2", "toggles_rotary.test", "timestamps.1"),
 				),
 			},
+			{
+				// Importing the resource should restore n, the active output, the counters and the trigger encoded in
+				// the ID, initialise the timestamps with the time of the import, and generate a new unique ID.
+				PreConfig: testAccFixTime("2022-02-02T02:02:02Z"),
+				ResourceName: "toggles_rotary.test",
+				ImportState: true,
+				ImportStateId: "n=4;active=2;counters=3,2,5,1;trigger=active;2",
+				ImportStateCheck: testAccImportStateCheckAttributes(map[string]string{
+					"n": "4",
+					"step": "1",
+					"outputs.0": "false",
+					"outputs.1": "false",
+					"outputs.2": "true",
+					"outputs.3": "false",
+					"counters.0": "3",
+					"counters.1": "2",
+					"counters.2": "5",
+					"counters.3": "1",
+					"active_output": "2",
+					"timestamps.#": "4",
+					"timestamps.0": "2022-02-02T02:02:02Z",
+					"timestamps.3": "2022-02-02T02:02:02Z",
+					"timestamps_unix.0": "1643767322",
+					"trigger": "active;2",
+				}),
+			},
+			{
+				// Importing the resource with a number of counters that does not match n should fail.
+				ResourceName: "toggles_rotary.test",
+				ImportState: true,
+				ImportStateId: "n=4;active=2;counters=1,1,1",
+				ExpectError: regexp.MustCompile("invalid number of counters"),
+			},
+			{
+				// Importing the resource with an active output that has never been activated should fail.
+				ResourceName: "toggles_rotary.test",
+				ImportState: true,
+				ImportStateId: "n=4;active=3;counters=1,1,1,0",
+				ExpectError: regexp.MustCompile("for the active output, should be at least 1"),
+			},
+			{
+				// Importing the resource with a malformed ID should fail.
+				ResourceName: "toggles_rotary.test",
+				ImportState: true,
+				ImportStateId: "4,2,1,1,1,0",
+				ExpectError: regexp.MustCompile("expected <key>=<value>"),
+			},
 			{
 				// Re-applying the resource with a changed trigger value should mark the old output as inactive, and the
 				// next output as active.
-				PreConfig: sleep,
+				PreConfig: testAccTick,
 				Config: testAccRotaryResource("active-3", n),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "false"),
@@ -94,12 +152,13 @@ func TestAccRotary(t *testing.T) {
 					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "1"),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "3"),
+					testAccTimeAfter("toggles_rotary.test", "timestamps.3", "toggles_rotary.test", "timestamps.2"),
 				),
 			},
 			{
 				// Re-applying the resource with a changed trigger value should set the 0-th output to active again when
 				// we wrap-around.
-				PreConfig: sleep,
+				PreConfig: testAccTick,
 				Config: testAccRotaryResource("wrap-around-active-0", n),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "true"),
@@ -111,17 +170,963 @@ func TestAccRotary(t *testing.T) {
 					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "1"),
 					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					testAccTimeAfter("toggles_rotary.test", "timestamps.0", "toggles_rotary.test", "timestamps.3"),
 				),
 			},
 		},
 	})
 }
 
-func testAccRotaryResource (trigger string, n int) string {
-	return fmt.Sprintf(`
-resource "toggles_rotary" "test" {
-  trigger = "%s"
-  n = "%d"
+func TestAccRotary_resize(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should set the 0th output to active.
+				Config: testAccRotaryResource("initial", 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should mark the next output as active.
+				Config: testAccRotaryResource("active-1", 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+				),
+			},
+			{
+				// Growing the resource with an un-changed trigger value should append inactive outputs without rotating.
+				Config: testAccRotaryResource("active-1", 5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.3", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.4", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "5"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.4", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "5"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should rotate over the grown outputs.
+				Config: testAccRotaryResource("active-2", 5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.3", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.4", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "5"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.4", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "5"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "2"),
+				),
+			},
+			{
+				// Shrinking the resource while the active output survives should keep the active output.
+				Config: testAccRotaryResource("active-2", 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "2"),
+				),
+			},
+			{
+				// Shrinking the resource with a changed trigger value should rotate over the remaining outputs.
+				Config: testAccRotaryResource("wrap-around-active-0", 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should mark the next output as active.
+				Config: testAccRotaryResource("active-1-again", 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should mark the next output as active.
+				Config: testAccRotaryResource("active-2-again", 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "2"),
+				),
+			},
+			{
+				// Shrinking the resource so that the active output is dropped should relocate the active output to the 0th
+				// output and increment its counter.
+				Config: testAccRotaryResource("active-2-again", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+				),
+			},
+		},
+	})
 }
-`, trigger, n)
+
+func TestAccRotary_defaultN(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource without n should create 2 outputs.
+				Config: testAccRotaryResourceWithoutN("initial"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "n", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRotary_unknownN(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Creating the resource with an n that is unknown during plan should set the initial outputs during apply.
+				PreConfig: testAccTick,
+				Config: testAccRotaryResourceWithUnknownN("initial", "initial", 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with an unknown, but un-changed n and an un-changed trigger should have the same
+				// output.
+				PreConfig: testAccTick,
+				Config: testAccRotaryResourceWithUnknownN("change-1", "initial", 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with an unknown, changed n and a changed trigger value should resize and rotate
+				// during apply.
+				PreConfig: testAccTick,
+				Config: testAccRotaryResourceWithUnknownN("change-2", "active-1", 4),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.3", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "4"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "4"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRotary_unknownTrigger(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Creating the resource with a trigger that is unknown during plan should set the 0th output to active.
+				PreConfig: testAccTick,
+				Config: testAccRotaryResourceWithUnknownTrigger("initial", 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with an un-changed trigger should have the same output.
+				PreConfig: testAccTick,
+				Config: testAccRotaryResourceWithUnknownTrigger("initial", 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with a trigger that is unknown during plan, and changes during apply, should
+				// mark the next output as active.
+				PreConfig: testAccTick,
+				Config: testAccRotaryResourceWithUnknownTrigger("change-1", 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.0", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.1", "true"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.2", "false"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRotary_triggers(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should set the 0th output to active without any trigger keys.
+				PreConfig: testAccTick,
+				Config: testAccRotaryResourceWithTriggers("initial"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "last_trigger_keys.#", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with un-changed triggers should have the same output.
+				PreConfig: testAccTick,
+				Config: testAccRotaryResourceWithTriggers("initial"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "last_trigger_keys.#", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with a trigger value that is unknown during plan, and changes during apply,
+				// should mark the next output as active and record the changed key.
+				PreConfig: testAccTick,
+				Config: testAccRotaryResourceWithTriggers("change-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "last_trigger_keys.#", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "last_trigger_keys.0", "timestamps"),
+				),
+			},
+			{
+				// Importing the resource should restore the triggers encoded in the ID, which may contain semicolons.
+				ResourceName: "toggles_rotary.test",
+				ImportState: true,
+				ImportStateId: `n=3;triggers={"static":"static","timestamps":"a;b"};active=1;counters=1,1,0`,
+				ImportStateCheck: testAccImportStateCheckAttributes(map[string]string{
+					"active_output": "1",
+					"triggers.%": "2",
+					"triggers.static": "static",
+					"triggers.timestamps": "a;b",
+				}),
+			},
+			{
+				// Importing the resource with triggers that are not followed by another field should fail.
+				ResourceName: "toggles_rotary.test",
+				ImportState: true,
+				ImportStateId: `n=3;active=1;counters=1,1,0;triggers={"static":"static"}trigger=x`,
+				ExpectError: regexp.MustCompile("unexpected characters"),
+			},
+		},
+	})
+}
+
+func TestAccRotary_triggerMode(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should set the 0th output to active.
+				Config: testAccRotaryResourceWithTriggerMode("on_change", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with an un-changed empty trigger should not rotate in on_change mode.
+				Config: testAccRotaryResourceWithTriggerMode("on_change", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger should mark the next output as active.
+				Config: testAccRotaryResourceWithTriggerMode("on_change", "change-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with a trigger changed to an empty value should mark the next output as active in
+				// on_change mode.
+				Config: testAccRotaryResourceWithTriggerMode("on_change", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger should not rotate in never mode.
+				Config: testAccRotaryResourceWithTriggerMode("never", "change-2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+				),
+			},
+			{
+				// Re-applying the resource with an un-changed trigger should rotate in always mode, and rotate again on the
+				// next plan.
+				Config: testAccRotaryResourceWithTriggerMode("always", "change-2"),
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRotary_values(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource with both n and values should fail.
+				Config: testAccRotaryResourceWithValuesAndN("initial", `["blue", "green", "purple"]`, 3),
+				ExpectError: regexp.MustCompile("conflicts with"),
+			},
+			{
+				// Applying the resource for the first time should create an output for each value and set the first value
+				// to active.
+				Config: testAccRotaryResourceWithValues("initial", `["blue", "green", "purple"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "n", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "blue"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "purple"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "green"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should mark the next value as active.
+				Config: testAccRotaryResourceWithValues("active-1", `["blue", "green", "purple"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "green"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "blue"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "purple"),
+				),
+			},
+			{
+				// Re-applying the resource with a renamed value and an un-changed trigger should only update the values.
+				Config: testAccRotaryResourceWithValues("active-1", `["blue", "green", "red"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "green"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "blue"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "red"),
+				),
+			},
+			{
+				// Re-applying the resource with an added value and a changed trigger value should resize and rotate.
+				Config: testAccRotaryResourceWithValues("active-2", `["blue", "green", "red", "yellow"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "n", "4"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "4"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "red"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "green"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "yellow"),
+				),
+			},
+			{
+				// Re-applying the resource without values should resize it to the default n.
+				Config: testAccRotaryResourceWithoutN("active-2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "n", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "outputs.#", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRotary_step(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should set the 0th output to active.
+				Config: testAccRotaryResourceWithStep("initial", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.4", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "a"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "d"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "c"),
+				),
+			},
+			{
+				// Applying the resource with a step that is a multiple of n should fail.
+				Config: testAccRotaryResourceWithStep("initial", 5),
+				ExpectError: regexp.MustCompile("should not be a multiple of n"),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should skip every other output.
+				Config: testAccRotaryResourceWithStep("active-1", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.4", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "c"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "a"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "e"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should skip every other output.
+				Config: testAccRotaryResourceWithStep("active-2", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "4"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.4", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "e"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "c"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "b"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should wrap around to the 1st output.
+				Config: testAccRotaryResourceWithStep("active-3", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.4", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "b"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "e"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "d"),
+				),
+			},
+			{
+				// Re-applying the resource with a negative step and a changed trigger value should rotate backwards.
+				Config: testAccRotaryResourceWithStep("active-4", -1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.4", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "a"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "b"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "e"),
+				),
+			},
+			{
+				// Re-applying the resource with a negative step and a changed trigger value should wrap around backwards.
+				Config: testAccRotaryResourceWithStep("active-5", -1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "4"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.4", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "e"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "a"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "d"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed step and an un-changed trigger should only update the values.
+				Config: testAccRotaryResourceWithStep("active-5", -2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "4"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.4", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "e"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "b"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "c"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRotary_pinnedOutput(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time with a pinned output should set the pinned output to active.
+				Config: testAccRotaryResourceWithPinnedOutput("initial", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+				),
+			},
+			{
+				// Applying the resource with a pinned output that is out of range should fail.
+				Config: testAccRotaryResourceWithPinnedOutput("initial", "5"),
+				ExpectError: regexp.MustCompile("pinned_output \\(5\\) should be between 0 and 3"),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should hold the pinned output active.
+				Config: testAccRotaryResourceWithPinnedOutput("active-1", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed pinned output should jump to the pinned output.
+				Config: testAccRotaryResourceWithPinnedOutput("active-1", "3"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "1"),
+				),
+			},
+			{
+				// Re-applying the resource without a pinned output should keep the last pinned output active.
+				Config: testAccRotaryResourceWithPinnedOutput("active-1", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "1"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should resume rotation from the last pinned output.
+				Config: testAccRotaryResourceWithPinnedOutput("active-2", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRotary_disabledOutputs(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time with a disabled 0th output should set the first enabled output to
+				// active.
+				Config: testAccRotaryResourceWithDisabledOutputs("initial", "[0]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "b"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "d"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "c"),
+				),
+			},
+			{
+				// Applying the resource with all outputs disabled should fail.
+				Config: testAccRotaryResourceWithDisabledOutputs("initial", "[0, 1, 2, 3]"),
+				ExpectError: regexp.MustCompile("all 4 outputs are disabled"),
+			},
+			{
+				// Applying the resource with a disabled output that is out of range should fail.
+				Config: testAccRotaryResourceWithDisabledOutputs("initial", "[4]"),
+				ExpectError: regexp.MustCompile("disabled output \\(4\\) should be between 0 and 3"),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should skip the disabled outputs.
+				Config: testAccRotaryResourceWithDisabledOutputs("active-1", "[0, 2]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "3"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "d"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "b"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "b"),
+				),
+			},
+			{
+				// Re-applying the resource with a disabled active output should rotate off it, skipping the disabled outputs.
+				Config: testAccRotaryResourceWithDisabledOutputs("active-1", "[0, 3]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "b"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "c"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "c"),
+				),
+			},
+			{
+				// Re-applying the resource with all outputs enabled and a changed trigger value should rotate to the next output.
+				Config: testAccRotaryResourceWithDisabledOutputs("active-2", "[]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.0", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.1", "2"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "counters.3", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_value", "c"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "previous_value", "b"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "next_value", "d"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRotary_timestampFormat(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should default to timestamps with nanoseconds.
+				PreConfig: testAccFixTime("2021-01-01T00:00:00.25Z"),
+				Config: testAccRotaryResourceWithTimestampFormat("initial", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamp_format", "rfc3339nano"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamps.0", "2021-01-01T00:00:00.25Z"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamps.1", "2021-01-01T00:00:00.25Z"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamps_unix.0", "1609459200"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamps_unix.1", "1609459200"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger within the same second should update the timestamp of
+				// the next output.
+				PreConfig: testAccFixTime("2021-01-01T00:00:00.75Z"),
+				Config: testAccRotaryResourceWithTimestampFormat("change-1", "null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamps.0", "2021-01-01T00:00:00.25Z"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamps.1", "2021-01-01T00:00:00.75Z"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed timestamp format should only reformat the timestamps.
+				PreConfig: testAccFixTime("2021-01-01T00:00:01Z"),
+				Config: testAccRotaryResourceWithTimestampFormat("change-1", `"unix"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamps.0", "1609459200"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamps.1", "1609459200"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger should update the timestamp in the timestamp format.
+				PreConfig: testAccFixTime("2021-01-01T00:00:02Z"),
+				Config: testAccRotaryResourceWithTimestampFormat("change-2", `"unix"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_rotary.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamps.0", "1609459202"),
+					resource.TestCheckResourceAttr("toggles_rotary.test", "timestamps_unix.0", "1609459202"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRotaryResource (trigger string, n int) string {
+	return fmt.Sprintf(`
+resource "toggles_rotary" "test" {
+  trigger = "%s"
+  n = "%d"
+}
+`, trigger, n)
+}
+
+
+func testAccRotaryResourceWithoutN (trigger string) string {
+	return fmt.Sprintf(`
+resource "toggles_rotary" "test" {
+  trigger = "%s"
+}
+`, trigger)
+}
+
+// testAccRotaryResourceWithUnknownN derives n from the timestamps of a leapfrog, which are unknown during plan whenever
+// the leapfrog is created or toggled.
+func testAccRotaryResourceWithUnknownN (leapfrogTrigger, trigger string, n int) string {
+	return fmt.Sprintf(`
+resource "toggles_leapfrog" "n" {
+  trigger = "%s"
+}
+
+resource "toggles_rotary" "test" {
+  trigger = "%s"
+  n = length("${toggles_leapfrog.n.alpha_timestamp}${toggles_leapfrog.n.beta_timestamp}") > 0 ? %d : 0
+}
+`, leapfrogTrigger, trigger, n)
+}
+
+// testAccRotaryResourceWithUnknownTrigger derives the trigger from the timestamps of a leapfrog, which are unknown
+// during plan whenever the leapfrog is created or toggled.
+func testAccRotaryResourceWithUnknownTrigger (leapfrogTrigger string, n int) string {
+	return fmt.Sprintf(`
+resource "toggles_leapfrog" "trigger" {
+  trigger = "%s"
+}
+
+resource "toggles_rotary" "test" {
+  trigger = "${toggles_leapfrog.trigger.alpha_timestamp}${toggles_leapfrog.trigger.beta_timestamp}"
+  n = %d
+}
+`, leapfrogTrigger, n)
+}
+
+// testAccRotaryResourceWithTriggers derives one of the triggers from the timestamps of a leapfrog, which are unknown
+// during plan whenever the leapfrog is created or toggled.
+func testAccRotaryResourceWithTriggers (leapfrogTrigger string) string {
+	return fmt.Sprintf(`
+resource "toggles_leapfrog" "trigger" {
+  trigger = "%s"
+}
+
+resource "toggles_rotary" "test" {
+  n = 3
+  triggers = {
+    static = "static"
+    timestamps = "${toggles_leapfrog.trigger.alpha_timestamp}${toggles_leapfrog.trigger.beta_timestamp}"
+  }
+}
+`, leapfrogTrigger)
+}
+
+func testAccRotaryResourceWithTriggerMode (mode, trigger string) string {
+	return fmt.Sprintf(`
+resource "toggles_rotary" "test" {
+  trigger_mode = "%s"
+  trigger = "%s"
+  n = 3
+}
+`, mode, trigger)
+}
+
+func testAccRotaryResourceWithValues (trigger string, values string) string {
+	return fmt.Sprintf(`
+resource "toggles_rotary" "test" {
+  trigger = "%s"
+  values = %s
+}
+`, trigger, values)
+}
+
+func testAccRotaryResourceWithValuesAndN (trigger string, values string, n int) string {
+	return fmt.Sprintf(`
+resource "toggles_rotary" "test" {
+  trigger = "%s"
+  values = %s
+  n = %d
+}
+`, trigger, values, n)
+}
+
+func testAccRotaryResourceWithStep (trigger string, step int) string {
+	return fmt.Sprintf(`
+resource "toggles_rotary" "test" {
+  trigger = "%s"
+  values = ["a", "b", "c", "d", "e"]
+  step = %d
+}
+`, trigger, step)
+}
+
+func testAccRotaryResourceWithPinnedOutput (trigger string, pinnedOutput string) string {
+	return fmt.Sprintf(`
+resource "toggles_rotary" "test" {
+  trigger = "%s"
+  n = 4
+  pinned_output = %s
+}
+`, trigger, pinnedOutput)
+}
+
+func testAccRotaryResourceWithDisabledOutputs (trigger string, disabledOutputs string) string {
+	return fmt.Sprintf(`
+resource "toggles_rotary" "test" {
+  trigger = "%s"
+  values = ["a", "b", "c", "d"]
+  disabled_outputs = %s
+}
+`, trigger, disabledOutputs)
+}
+
+
+func testAccRotaryResourceWithTimestampFormat (trigger string, timestampFormat string) string {
+	return fmt.Sprintf(`
+resource "toggles_rotary" "test" {
+  trigger = "%s"
+  timestamp_format = %s
+}
+`, trigger, timestampFormat)
 }
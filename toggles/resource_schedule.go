@@ -0,0 +1,340 @@
+package toggles
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/robfig/cron/v3"
+	"time"
+)
+
+func resourceSchedule() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceScheduleCreate,
+		ReadContext:   resourceScheduleRead,
+		UpdateContext: resourceScheduleUpdate,
+		DeleteContext: resourceScheduleDelete,
+		CustomizeDiff: customizeDiffSchedule,
+		Schema: map[string]*schema.Schema {
+			"cron": {
+				Type: schema.TypeString,
+				Description: "A standard cron expression with 5 fields, or a descriptor such as @daily, that schedules the toggles.",
+				Required: true,
+				ValidateFunc: validateCron,
+			},
+			"timezone": {
+				Type: schema.TypeString,
+				Description: "The IANA time zone in which the cron expression is evaluated, e.g. Europe/Amsterdam. Defaults to the timezone of the provider, or UTC.",
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validateTimezone,
+			},
+			"n": {
+				Type: schema.TypeInt,
+				Description: "The number of outputs. Should be between 2 and 256. Defaults to 2.",
+				Optional: true,
+				Default: 2,
+				ValidateFunc: validation.IntBetween(2, 256),
+			},
+			"outputs": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeBool,
+				},
+				Description: "A list of n boolean outputs.",
+				Computed: true,
+			},
+			"active_output": {
+				Type: schema.TypeInt,
+				Description: "The 0-index based number of the active output.",
+				Computed: true,
+			},
+			"counters": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+				Description: "A list of counters denoting the number of times the corresponding output was set to true.",
+				Computed: true,
+			},
+			"last_toggle_timestamp": {
+				Type: schema.TypeString,
+				Description: "An RFC3339 timestamp of the scheduled time of the last toggle, or of the creation of the schedule.",
+				Computed: true,
+			},
+			"next_toggle_timestamp": {
+				Type: schema.TypeString,
+				Description: "An RFC3339 timestamp of the scheduled time of the next toggle.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+// validateCron validates that the value is a valid standard cron expression.
+func validateCron(value interface{}, key string) ([]string, []error) {
+	if _, err := cron.ParseStandard(value.(string)); err != nil {
+		return nil, []error{fmt.Errorf("expected %s to be a valid cron expression, got %s: %+v", key, value, err)}
+	}
+
+	return nil, nil
+}
+
+// validateTimezone validates that the value is a known IANA time zone.
+func validateTimezone(value interface{}, key string) ([]string, []error) {
+	if _, err := time.LoadLocation(value.(string)); err != nil {
+		return nil, []error{fmt.Errorf("expected %s to be a valid time zone, got %s: %+v", key, value, err)}
+	}
+
+	return nil, nil
+}
+
+// setNewDefaultTimezone sets the time zone of a new schedule to the time zone of the provider when it is not configured.
+func setNewDefaultTimezone(d *schema.ResourceDiff, meta *providerMeta) error {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.GetAttr("timezone").IsNull() {
+		return nil
+	}
+
+	if err := d.SetNew("timezone", meta.Location.String()); err != nil {
+		return fmt.Errorf("could not set timezone: %+v", err)
+	}
+
+	return nil
+}
+
+// readSchedule parses the cron expression and loads the time zone of a schedule.
+func readSchedule(d resourceGetter) (cron.Schedule, *time.Location, error) {
+	location, err := time.LoadLocation(d.Get("timezone").(string))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load timezone: %+v", err)
+	}
+
+	schedule, err := cron.ParseStandard(d.Get("cron").(string))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse cron: %+v", err)
+	}
+
+	return schedule, location, nil
+}
+
+// lastScheduledToggle returns the last time at or before now at which the schedule toggles, starting from next, which
+// should be at or before now. When several scheduled times have passed since the last toggle, the schedule toggles
+// only once, at the last of them.
+func lastScheduledToggle(schedule cron.Schedule, next time.Time, now time.Time) time.Time {
+	last := next
+	for {
+		following := schedule.Next(last)
+		if following.IsZero() || following.After(now) {
+			return last
+		}
+
+		last = following
+	}
+}
+
+// scheduleKnown returns whether the cron expression, the time zone and n are known during plan.
+func scheduleKnown(d *schema.ResourceDiff) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		return d.NewValueKnown("cron") && d.NewValueKnown("timezone") && d.NewValueKnown("n")
+	}
+
+	return config.GetAttr("cron").IsKnown() && config.GetAttr("timezone").IsKnown() && config.GetAttr("n").IsKnown()
+}
+
+// nextSchedule returns whether the schedule toggles now, and the scheduled times of the last and next toggle
+// afterwards. The next toggle is re-computed from the last toggle when the cron expression or time zone changed.
+// A frozen provider never toggles, so the toggle happens at the first apply after the freeze.
+func nextSchedule(d resourceGetter, meta *providerMeta) (bool, time.Time, time.Time, error) {
+	schedule, location, err := readSchedule(d)
+	if err != nil {
+		return false, time.Time{}, time.Time{}, err
+	}
+
+	rawLast, _ := d.GetChange("last_toggle_timestamp")
+	last, err := time.Parse(time.RFC3339, rawLast.(string))
+	if err != nil {
+		return false, time.Time{}, time.Time{}, fmt.Errorf("could not parse last_toggle_timestamp: %+v", err)
+	}
+
+	last = last.In(location)
+	next := schedule.Next(last)
+
+	if !d.HasChange("cron") && !d.HasChange("timezone") {
+		// An empty next toggle timestamp denotes a schedule that never toggles again.
+		next = time.Time{}
+
+		rawNext, _ := d.GetChange("next_toggle_timestamp")
+		if rawNext.(string) != "" {
+			if next, err = time.Parse(time.RFC3339, rawNext.(string)); err != nil {
+				return false, time.Time{}, time.Time{}, fmt.Errorf("could not parse next_toggle_timestamp: %+v", err)
+			}
+
+			next = next.In(location)
+		}
+	}
+
+	now := meta.Clock()
+	if next.IsZero() || now.Before(next) || meta.Frozen {
+		return false, last, next, nil
+	}
+
+	last = lastScheduledToggle(schedule, next, now)
+
+	return true, last, schedule.Next(last), nil
+}
+
+// setScheduleTimestamps sets the timestamps of the last and next toggle using set, which is either SetNew of
+// schema.ResourceDiff or Set of schema.ResourceData. A zero next toggle, when the schedule never toggles again, is set
+// as an empty timestamp.
+func setScheduleTimestamps(set func(string, interface{}) error, last time.Time, next time.Time) error {
+	if err := set("last_toggle_timestamp", last.Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("could not set last_toggle_timestamp: %+v", err)
+	}
+
+	nextTimestamp := ""
+	if !next.IsZero() {
+		nextTimestamp = next.Format(time.RFC3339)
+	}
+
+	if err := set("next_toggle_timestamp", nextTimestamp); err != nil {
+		return fmt.Errorf("could not set next_toggle_timestamp: %+v", err)
+	}
+
+	return nil
+}
+
+// customizeDiffSchedule ensures that we show changes in the diff phase.
+// During creation it sets the initial outputs, the timestamps are set in resourceScheduleCreate. During an update it
+// toggles the output when the next scheduled toggle has passed, and sets the timestamps to the scheduled times, so
+// they don't change when the plan is re-computed during apply.
+// When the cron expression, time zone or n are unknown during plan, the outputs are marked as computed and set during
+// apply instead.
+func customizeDiffSchedule(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	meta := getProviderMeta(m)
+
+	if d.Id() == "" {
+		if err := setNewDefaultTimezone(d, meta); err != nil {
+			return err
+		}
+	}
+
+	if !scheduleKnown(d) {
+		for _, key := range []string{"outputs", "active_output", "counters", "last_toggle_timestamp", "next_toggle_timestamp"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return fmt.Errorf("could not mark %s as new computed: %+v", key, err)
+			}
+		}
+
+		return nil
+	}
+
+	n := d.Get("n").(int)
+
+	// New resource: set the initial state.
+	if d.Id() == "" {
+		return setRotaryState(d.SetNew, newRotaryState(n, 0))
+	}
+
+	toggle, last, next, err := nextSchedule(d, meta)
+	if err != nil {
+		return err
+	}
+
+	old := oldRotaryState(d)
+	if len(old.Counters) != n || toggle {
+		state, err := old.next(rotaryConfig{N: n, Step: 1}, toggle)
+		if err != nil {
+			return err
+		}
+
+		if err := setRotaryState(d.SetNew, state); err != nil {
+			return err
+		}
+	}
+
+	if toggle || d.HasChange("cron") || d.HasChange("timezone") {
+		return setScheduleTimestamps(d.SetNew, last, next)
+	}
+
+	return nil
+}
+
+// resourceScheduleCreate ensures the resource's id is set and initialises the timestamps.
+// The initial outputs are set in customizeDiffSchedule, unless the cron expression, time zone or n were unknown during
+// plan.
+func resourceScheduleCreate(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !d.GetRawPlan().GetAttr("active_output").IsKnown() {
+		if err := setRotaryState(d.Set, newRotaryState(d.Get("n").(int), 0)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	schedule, location, err := readSchedule(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	now := getProviderMeta(m).Clock().In(location).Truncate(time.Second)
+	if err := setScheduleTimestamps(d.Set, now, schedule.Next(now)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	id, err := newToggleID()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id)
+
+	return diags
+}
+
+// resourceScheduleRead is a noop as all attributes are internal.
+func resourceScheduleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	return diags
+}
+
+// resourceScheduleUpdate sets the outputs and timestamps when the cron expression, time zone or n were unknown during
+// plan.
+func resourceScheduleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if d.GetRawPlan().GetAttr("active_output").IsKnown() {
+		return diags
+	}
+
+	toggle, last, next, err := nextSchedule(d, getProviderMeta(m))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	state, err := oldRotaryState(d).next(rotaryConfig{N: d.Get("n").(int), Step: 1}, toggle)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setRotaryState(d.Set, state); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setScheduleTimestamps(d.Set, last, next); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// resourceScheduleDelete is a noop, as no external resource are being managed.
+func resourceScheduleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	return diags
+}
@@ -0,0 +1,214 @@
+package toggles
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/robfig/cron/v3"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestLastScheduledToggle(t *testing.T) {
+	schedule, err := cron.ParseStandard("0 * * * *")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	next := time.Date(2021, 1, 1, 11, 0, 0, 0, time.UTC)
+	for now, expected := range map[time.Time]time.Time{
+		time.Date(2021, 1, 1, 11, 0, 0, 0, time.UTC):  time.Date(2021, 1, 1, 11, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 1, 11, 59, 0, 0, time.UTC): time.Date(2021, 1, 1, 11, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 1, 14, 45, 0, 0, time.UTC): time.Date(2021, 1, 1, 14, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC):   time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+	} {
+		if last := lastScheduledToggle(schedule, next, now); !last.Equal(expected) {
+			t.Fatalf("expected the last toggle at %s to be %s, got %s", now, expected, last)
+		}
+	}
+}
+
+func TestAccSchedule(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should set the 0th output and schedule the next toggle.
+				PreConfig: testAccFixTime("2021-01-01T10:30:00Z"),
+				Config: testAccScheduleResource("0 * * * *", "UTC"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("toggles_schedule.test", "id", testAccUUIDRegexp),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "outputs.1", "false"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "last_toggle_timestamp", "2021-01-01T10:30:00Z"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2021-01-01T11:00:00Z"),
+				),
+			},
+			{
+				// Re-applying the resource before the next scheduled toggle should not toggle.
+				PreConfig: testAccFixTime("2021-01-01T10:59:59Z"),
+				Config: testAccScheduleResource("0 * * * *", "UTC"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "last_toggle_timestamp", "2021-01-01T10:30:00Z"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2021-01-01T11:00:00Z"),
+				),
+			},
+			{
+				// Re-applying the resource at the next scheduled toggle should toggle.
+				PreConfig: testAccFixTime("2021-01-01T11:00:00Z"),
+				Config: testAccScheduleResource("0 * * * *", "UTC"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "outputs.0", "false"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "outputs.1", "true"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "last_toggle_timestamp", "2021-01-01T11:00:00Z"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2021-01-01T12:00:00Z"),
+				),
+			},
+			{
+				// Re-applying the resource after several scheduled toggles should toggle only once.
+				PreConfig: testAccFixTime("2021-01-01T14:45:00Z"),
+				Config: testAccScheduleResource("0 * * * *", "UTC"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "last_toggle_timestamp", "2021-01-01T14:00:00Z"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2021-01-01T15:00:00Z"),
+				),
+			},
+			{
+				// Changing the cron expression should re-schedule from the last toggle, and toggle if that has passed.
+				PreConfig: testAccFixTime("2021-01-01T14:50:00Z"),
+				Config: testAccScheduleResource("30 * * * *", "UTC"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "last_toggle_timestamp", "2021-01-01T14:30:00Z"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2021-01-01T15:30:00Z"),
+				),
+			},
+			{
+				// Changing the cron expression to a later time should re-schedule without toggling.
+				PreConfig: testAccFixTime("2021-01-01T14:55:00Z"),
+				Config: testAccScheduleResource("45 15 * * *", "UTC"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "last_toggle_timestamp", "2021-01-01T14:30:00Z"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2021-01-01T15:45:00Z"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSchedule_timezone(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should schedule the next toggle in the time zone.
+				PreConfig: testAccFixTime("2021-01-01T07:00:00Z"),
+				Config: testAccScheduleResource("0 9 * * *", "Europe/Amsterdam"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "last_toggle_timestamp", "2021-01-01T08:00:00+01:00"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2021-01-01T09:00:00+01:00"),
+				),
+			},
+			{
+				// Re-applying the resource at the scheduled time in the time zone should toggle.
+				PreConfig: testAccFixTime("2021-01-01T08:00:00Z"),
+				Config: testAccScheduleResource("0 9 * * *", "Europe/Amsterdam"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "last_toggle_timestamp", "2021-01-01T09:00:00+01:00"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2021-01-02T09:00:00+01:00"),
+				),
+			},
+			{
+				// Changing the time zone should re-schedule from the last toggle.
+				PreConfig: testAccFixTime("2021-01-01T08:30:00Z"),
+				Config: testAccScheduleResource("0 9 * * *", "UTC"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "last_toggle_timestamp", "2021-01-01T08:00:00Z"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2021-01-01T09:00:00Z"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSchedule_never(t *testing.T) {
+	defer testAccRestoreTime()
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// A cron expression without a next scheduled time should leave the next toggle empty.
+				PreConfig: testAccFixTime("2021-01-01T10:30:00Z"),
+				Config: testAccScheduleResource("0 0 30 2 *", "UTC"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", ""),
+				),
+			},
+			{
+				// Re-applying the resource should never toggle.
+				PreConfig: testAccFixTime("2022-01-01T10:30:00Z"),
+				Config: testAccScheduleResource("0 0 30 2 *", "UTC"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "last_toggle_timestamp", "2021-01-01T10:30:00Z"),
+				),
+			},
+			{
+				// Changing the cron expression should schedule the next toggle again.
+				PreConfig: testAccFixTime("2022-01-01T10:45:00Z"),
+				Config: testAccScheduleResource("0 * * * *", "UTC"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_schedule.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "last_toggle_timestamp", "2022-01-01T10:00:00Z"),
+					resource.TestCheckResourceAttr("toggles_schedule.test", "next_toggle_timestamp", "2022-01-01T11:00:00Z"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSchedule_invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// An invalid cron expression should be rejected.
+				Config: testAccScheduleResource("0 * * *", "UTC"),
+				ExpectError: regexp.MustCompile("expected cron to be a valid cron expression"),
+			},
+			{
+				// An unknown time zone should be rejected.
+				Config: testAccScheduleResource("0 * * * *", "Mars/Olympus_Mons"),
+				ExpectError: regexp.MustCompile("expected timezone to be a valid time zone"),
+			},
+			{
+				Config: testAccScheduleResource("0 * * * *", "UTC"),
+			},
+		},
+	})
+}
+
+func testAccScheduleResource (cron string, timezone string) string {
+	return fmt.Sprintf(`
+resource "toggles_schedule" "test" {
+  cron = "%s"
+  timezone = "%s"
+}
+`, cron, timezone)
+}
@@ -0,0 +1,333 @@
+package toggles
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceWeighted() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceWeightedCreate,
+		ReadContext:   resourceWeightedRead,
+		UpdateContext: resourceWeightedUpdate,
+		DeleteContext: resourceWeightedDelete,
+		CustomizeDiff: customizeDiffWeighted,
+		Schema: map[string]*schema.Schema {
+			"trigger": {
+				Type: schema.TypeString,
+				Description: "An arbitrary string value that, when changed, toggles the output.",
+				Optional: true,
+			},
+			"triggers": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A map of arbitrary string values that, when any of them changes, toggles the output.",
+				Optional: true,
+			},
+			"trigger_mode": {
+				Type: schema.TypeString,
+				Description: "Determines when the output is toggled: on_change of the trigger or triggers, always or never. When not set, the output is toggled on each apply if both the trigger and triggers are empty, and on changes otherwise.",
+				Optional: true,
+				ValidateFunc: validation.StringInSlice(triggerModes, false),
+			},
+			"last_trigger_keys": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The sorted keys of the triggers that were added, removed or changed on the last toggle.",
+				Computed: true,
+			},
+			"weights": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				Description: "A list of non-negative integer weights, one for each output. An output is activated in proportion to its weight.",
+				Required: true,
+				MinItems: 2,
+				MaxItems: 256,
+			},
+			"outputs": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeBool,
+				},
+				Description: "A list of boolean outputs, one for each weight.",
+				Computed: true,
+			},
+			"active_output": {
+				Type: schema.TypeInt,
+				Description: "The 0-index based number of the active output.",
+				Computed: true,
+			},
+			"counters": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+				Description: "A list of counters denoting the number of times the corresponding output was set to true.",
+				Computed: true,
+			},
+			"current_weights": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+				Description: "The current weights of the smooth weighted round-robin, which determine the next active output.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+// weightedState holds the attributes of a weighted toggle that change when it rotates. It extends the state of a rotary
+// with the current weights of the smooth weighted round-robin.
+type weightedState struct {
+	rotaryState
+	CurrentWeights []int
+}
+
+// newWeightedState returns the initial state of a weighted toggle, which has the output that is selected first active.
+func newWeightedState(weights []int) weightedState {
+	return weightedState{
+		rotaryState:    rotaryState{Counters: make([]int, len(weights), len(weights))},
+		CurrentWeights: make([]int, len(weights), len(weights)),
+	}.rotate(weights)
+}
+
+// rotate returns the state of the weighted toggle after selecting the next active output using smooth weighted
+// round-robin: each current weight is increased by its weight, the output with the highest current weight becomes
+// active (the lowest output on a tie), and its current weight is decreased by the sum of all weights. This spreads the
+// activations of each output evenly over the sequence, e.g. weights [3, 1] activate the outputs 0, 0, 1, 0 repeatedly.
+func (s weightedState) rotate(weights []int) weightedState {
+	state := s.resize(len(s.Counters))
+
+	currentWeights := make([]int, len(s.CurrentWeights), len(s.CurrentWeights))
+	copy(currentWeights, s.CurrentWeights)
+
+	total := 0
+	activeOutput := 0
+	for i, weight := range weights {
+		total += weight
+		currentWeights[i] += weight
+
+		if currentWeights[i] > currentWeights[activeOutput] {
+			activeOutput = i
+		}
+	}
+
+	currentWeights[activeOutput] -= total
+	state.Counters[activeOutput] += 1
+	state.ActiveOutput = activeOutput
+
+	return weightedState{
+		rotaryState:    state,
+		CurrentWeights: currentWeights,
+	}
+}
+
+// next returns the state of the weighted toggle after resizing it to the number of weights, and rotating it if rotate
+// is true.
+//
+// Resizing keeps the counters of the remaining outputs like a rotary, and resets the current weights to restart the
+// round-robin. If the active output is dropped, the toggle rotates to select a new active output.
+func (s weightedState) next(weights []int, rotate bool) weightedState {
+	n := len(weights)
+	if n != len(s.Counters) {
+		s = weightedState{
+			rotaryState:    s.resize(n),
+			CurrentWeights: make([]int, n, n),
+		}
+
+		if s.ActiveOutput >= n {
+			rotate = true
+		}
+	}
+
+	if !rotate {
+		return s
+	}
+
+	return s.rotate(weights)
+}
+
+// oldWeightedState reads the state of the weighted toggle before the current change.
+// Both schema.ResourceData and schema.ResourceDiff can be used to read the old state.
+func oldWeightedState(d resourceGetter) weightedState {
+	currentWeights, _ := d.GetChange("current_weights")
+
+	return weightedState{
+		rotaryState:    oldRotaryState(d),
+		CurrentWeights: toInts(currentWeights.([]interface{})),
+	}
+}
+
+// setWeightedState sets all attributes derived from the state of the weighted toggle using set, which is either SetNew
+// of schema.ResourceDiff or Set of schema.ResourceData.
+func setWeightedState(set func(string, interface{}) error, s weightedState) error {
+	if err := setRotaryState(set, s.rotaryState); err != nil {
+		return err
+	}
+
+	if err := set("current_weights", fromInts(s.CurrentWeights)); err != nil {
+		return fmt.Errorf("could not set current_weights: %+v", err)
+	}
+
+	return nil
+}
+
+// readWeights reads the weights, and returns an error when none of them is positive.
+func readWeights(d resourceGetter) ([]int, error) {
+	weights := toInts(d.Get("weights").([]interface{}))
+
+	for _, weight := range weights {
+		if weight > 0 {
+			return weights, nil
+		}
+	}
+
+	return nil, fmt.Errorf("weights (%v) should contain at least one positive weight", weights)
+}
+
+// weightsKnown returns whether the weights are known during plan.
+func weightsKnown(d *schema.ResourceDiff) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		return d.NewValueKnown("weights")
+	}
+
+	return config.GetAttr("weights").IsWhollyKnown()
+}
+
+// customizeDiffWeighted ensures that we show changes in the diff phase.
+// As all attributes are set during the diff-phase it functions as both the create and update function.
+// When the weights or the trigger are unknown during plan, the outputs are marked as computed and set during apply
+// instead.
+func customizeDiffWeighted(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	meta := getProviderMeta(m)
+
+	if d.Id() != "" {
+		if err := clearLastTriggerKeys(d); err != nil {
+			return err
+		}
+	}
+
+	if !weightsKnown(d) || (d.Id() != "" && !triggersKnown(d, meta)) {
+		for _, key := range []string{"outputs", "active_output", "counters", "current_weights", "last_trigger_keys"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return fmt.Errorf("could not mark %s as new computed: %+v", key, err)
+			}
+		}
+
+		return nil
+	}
+
+	weights, err := readWeights(d)
+	if err != nil {
+		return err
+	}
+
+	// New resource: set the initial state.
+	if d.Id() == "" {
+		if err := d.SetNew("last_trigger_keys", []interface{}{}); err != nil {
+			return fmt.Errorf("could not set last_trigger_keys: %+v", err)
+		}
+
+		return setWeightedState(d.SetNew, newWeightedState(weights))
+	}
+
+	old := oldWeightedState(d)
+	rotate := shouldToggle(d, meta)
+	if len(old.Counters) == len(weights) && !rotate {
+		return nil
+	}
+
+	if rotate {
+		if err := setLastTriggerKeys(d.SetNew, d); err != nil {
+			return err
+		}
+	}
+
+	return setWeightedState(d.SetNew, old.next(weights, rotate))
+}
+
+// resourceWeightedCreate ensures the resource's id is set.
+// The initial attribute values are set in customizeDiffWeighted, unless the weights were unknown during plan.
+func resourceWeightedCreate(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !d.GetRawPlan().GetAttr("active_output").IsKnown() {
+		weights, err := readWeights(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := setWeightedState(d.Set, newWeightedState(weights)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := d.Set("last_trigger_keys", []interface{}{}); err != nil {
+			return diag.Errorf("could not set last_trigger_keys: %+v", err)
+		}
+	}
+
+	id, err := newToggleID()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id)
+
+	return diags
+}
+
+// resourceWeightedRead is a noop as all attributes are internal.
+func resourceWeightedRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	return diags
+}
+
+// resourceWeightedUpdate sets the outputs when the weights or the trigger were unknown during plan.
+func resourceWeightedUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if d.GetRawPlan().GetAttr("active_output").IsKnown() {
+		return diags
+	}
+
+	weights, err := readWeights(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	rotate := shouldToggle(d, getProviderMeta(m))
+	if err := setWeightedState(d.Set, oldWeightedState(d).next(weights, rotate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	lastTriggerKeys, _ := d.GetChange("last_trigger_keys")
+	if rotate {
+		lastTriggerKeys = changedTriggerKeys(d)
+	}
+
+	if err := d.Set("last_trigger_keys", lastTriggerKeys); err != nil {
+		return diag.Errorf("could not set last_trigger_keys: %+v", err)
+	}
+
+	return diags
+}
+
+// resourceWeightedDelete is a noop, as no external resource are being managed.
+func resourceWeightedDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	return diags
+}
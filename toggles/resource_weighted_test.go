@@ -0,0 +1,216 @@
+package toggles
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestWeightedStateRotate(t *testing.T) {
+	weights := []int{5, 1, 1}
+	expected := []int{0, 0, 1, 0, 2, 0, 0}
+
+	state := newWeightedState(weights)
+	actual := []int{state.ActiveOutput}
+	for len(actual) < len(expected) {
+		state = state.rotate(weights)
+		actual = append(actual, state.ActiveOutput)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected sequence %v, got %v", expected, actual)
+	}
+
+	// After a full sequence the current weights should be reset, and each output activated according to its weight.
+	if !reflect.DeepEqual([]int{0, 0, 0}, state.CurrentWeights) {
+		t.Fatalf("expected current weights [0 0 0], got %v", state.CurrentWeights)
+	}
+
+	if !reflect.DeepEqual(weights, state.Counters) {
+		t.Fatalf("expected counters %v, got %v", weights, state.Counters)
+	}
+}
+
+func TestAccWeighted(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the resource for the first time should set the output with the highest weight to active.
+				Config: testAccWeightedResource("initial", "[3, 1]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_weighted.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.#", "2"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.0", "-1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.1", "1"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should keep the output with the highest weight active.
+				Config: testAccWeightedResource("active-1", "[3, 1]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_weighted.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.#", "2"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.0", "-2"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.1", "2"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should set the output with the lowest weight to active.
+				Config: testAccWeightedResource("active-2", "[3, 1]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_weighted.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.#", "2"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.1", "true"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.0", "1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.1", "-1"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should complete the sequence.
+				Config: testAccWeightedResource("active-3", "[3, 1]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_weighted.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.#", "2"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.0", "3"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.0", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.1", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with an added weight and an un-changed trigger should only resize.
+				Config: testAccWeightedResource("active-3", "[3, 1, 2]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_weighted.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.0", "3"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.0", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.1", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.2", "0"),
+				),
+			},
+			{
+				// Applying the resource without a positive weight should fail.
+				Config: testAccWeightedResource("active-3", "[0, 0]"),
+				ExpectError: regexp.MustCompile("should contain at least one positive weight"),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should use the added weight.
+				Config: testAccWeightedResource("active-4", "[3, 1, 2]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_weighted.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.0", "true"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.0", "4"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.2", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.0", "-3"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.1", "1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.2", "2"),
+				),
+			},
+			{
+				// Re-applying the resource with a changed trigger value should set the output with the highest current weight to active.
+				Config: testAccWeightedResource("active-5", "[3, 1, 2]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_weighted.test", "active_output", "2"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.#", "3"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "outputs.2", "true"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.0", "4"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.1", "1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.2", "1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.0", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.1", "2"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "current_weights.2", "-2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWeighted_unknownWeights(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Creating the resource with weights that are unknown during plan should set the initial state during apply.
+				Config: testAccWeightedResourceWithUnknownWeights("initial", "initial"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_weighted.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.0", "1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.1", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with weights that are unknown during plan, and a changed trigger value, should
+				// rotate during apply.
+				Config: testAccWeightedResourceWithUnknownWeights("change-1", "active-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_weighted.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.1", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "last_trigger_keys.#", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with weights that are unknown during plan, and an un-changed trigger value,
+				// should not rotate.
+				Config: testAccWeightedResourceWithUnknownWeights("change-2", "active-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_weighted.test", "active_output", "0"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.1", "0"),
+				),
+			},
+			{
+				// Re-applying the resource with weights that are unknown during plan, and a changed trigger value, should
+				// set the output with the lowest weight to active during apply.
+				Config: testAccWeightedResourceWithUnknownWeights("change-3", "active-2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("toggles_weighted.test", "active_output", "1"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.0", "2"),
+					resource.TestCheckResourceAttr("toggles_weighted.test", "counters.1", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccWeightedResource (trigger string, weights string) string {
+	return fmt.Sprintf(`
+resource "toggles_weighted" "test" {
+  trigger = "%s"
+  weights = %s
+}
+`, trigger, weights)
+}
+
+// testAccWeightedResourceWithUnknownWeights derives the weights from the timestamps of a leapfrog, which are unknown
+// during plan whenever the leapfrog is created or toggled.
+func testAccWeightedResourceWithUnknownWeights (leapfrogTrigger, trigger string) string {
+	return fmt.Sprintf(`
+resource "toggles_leapfrog" "weights" {
+  trigger = "%s"
+}
+
+resource "toggles_weighted" "test" {
+  trigger = "%s"
+  weights = [length("${toggles_leapfrog.weights.alpha_timestamp}${toggles_leapfrog.weights.beta_timestamp}") > 0 ? 3 : 0, 1]
+}
+`, leapfrogTrigger, trigger)
+}
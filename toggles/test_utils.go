@@ -4,9 +4,14 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"os"
+	"regexp"
 	"time"
 )
 
+// testAccUUIDRegexp matches the unique IDs generated for the toggles
+var testAccUUIDRegexp = regexp.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$")
+
 // testAccValidRFC3339 checks if the attribute value on the resource is a valid RFC3339 timestamp
 func testAccValidRFC3339(name, attr string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
@@ -69,7 +74,54 @@ func testAccTimeAfter(nameFirst, attrFirst, nameSecond, attrSecond string) resou
 	}
 }
 
-// The sleep function sleeps for 1 second, to allow time to pass
-func sleep() {
-	time.Sleep(1 * time.Second)
+// testAccImportStateCheckAttributes checks whether the single imported resource has a unique ID and the expected
+// attribute values
+func testAccImportStateCheckAttributes(expected map[string]string) resource.ImportStateCheckFunc {
+	return func(states []*terraform.InstanceState) error {
+		if len(states) != 1 {
+			return fmt.Errorf("Expected 1 imported resource, got %d", len(states))
+		}
+
+		state := states[0]
+		if !testAccUUIDRegexp.MatchString(state.ID) {
+			return fmt.Errorf("Not a unique ID: %s", state.ID)
+		}
+
+		for attr, value := range expected {
+			if state.Attributes[attr] != value {
+				return fmt.Errorf("Attribute %s expected %q, got %q", attr, value, state.Attributes[attr])
+			}
+		}
+
+		return nil
+	}
+}
+
+// testAccBaseTime is the time at which the clock of the toggles starts when the acceptance tests let time pass
+const testAccBaseTime = "2021-01-01T00:00:00Z"
+
+// testAccFixTime returns a function that fixes the time seen by the toggles to the RFC3339 timestamp, to be used as the
+// PreConfig of a test step. Use testAccRestoreTime to restore the current time afterwards
+func testAccFixTime(timestamp string) func() {
+	return func() {
+		if err := os.Setenv("TOGGLES_FIXED_TIME", timestamp); err != nil {
+			panic(fmt.Sprintf("Could not set TOGGLES_FIXED_TIME: %+v", err))
+		}
+	}
+}
+
+// testAccTick advances the fixed time seen by the toggles by 1 second, to allow time to pass without waiting. When the
+// time was not fixed yet, the clock starts at testAccBaseTime
+func testAccTick() {
+	now, err := time.Parse(time.RFC3339, os.Getenv("TOGGLES_FIXED_TIME"))
+	if err != nil {
+		now, _ = time.Parse(time.RFC3339, testAccBaseTime)
+	}
+
+	testAccFixTime(now.Add(1 * time.Second).Format(time.RFC3339))()
+}
+
+// testAccRestoreTime restores the time seen by the toggles to the current time
+func testAccRestoreTime() {
+	os.Unsetenv("TOGGLES_FIXED_TIME")
 }
@@ -0,0 +1,116 @@
+package toggles
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strconv"
+	"time"
+)
+
+const (
+	// timestampFormatRFC3339 formats timestamps as RFC3339 timestamps with a precision of seconds.
+	timestampFormatRFC3339 = "rfc3339"
+	// timestampFormatRFC3339Nano formats timestamps as RFC3339 timestamps with a precision of nanoseconds.
+	timestampFormatRFC3339Nano = "rfc3339nano"
+	// timestampFormatUnix formats timestamps as the number of seconds since the unix epoch.
+	timestampFormatUnix = "unix"
+	// timestampFormatUnixMs formats timestamps as the number of milliseconds since the unix epoch.
+	timestampFormatUnixMs = "unix_ms"
+)
+
+// timestampFormats are the valid values of the timestamp_format attribute.
+var timestampFormats = []string{timestampFormatRFC3339, timestampFormatRFC3339Nano, timestampFormatUnix, timestampFormatUnixMs}
+
+// formatTimestamp formats t according to the timestamp format.
+// An empty format, of toggles that were imported or created by an older version of the provider, formats as RFC3339.
+func formatTimestamp(t time.Time, format string) string {
+	switch format {
+	case timestampFormatRFC3339Nano:
+		return t.Format(time.RFC3339Nano)
+	case timestampFormatUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case timestampFormatUnixMs:
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
+
+// parseTimestamp parses a timestamp that was formatted according to the timestamp format.
+func parseTimestamp(value string, format string) (time.Time, error) {
+	switch format {
+	case timestampFormatUnix:
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return time.Unix(seconds, 0).UTC(), nil
+	case timestampFormatUnixMs:
+		milliseconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return time.Unix(0, milliseconds*int64(time.Millisecond)).UTC(), nil
+	default:
+		// Parsing RFC3339 also accepts fractional seconds.
+		return time.Parse(time.RFC3339, value)
+	}
+}
+
+// reformatTimestamp formats a timestamp that was formatted according to the old format according to the new format.
+// An empty timestamp stays empty.
+func reformatTimestamp(value string, oldFormat string, newFormat string) (string, error) {
+	if value == "" || oldFormat == newFormat {
+		return value, nil
+	}
+
+	t, err := parseTimestamp(value, oldFormat)
+	if err != nil {
+		return "", err
+	}
+
+	return formatTimestamp(t, newFormat), nil
+}
+
+// unixTimestamp returns the number of seconds since the unix epoch of a timestamp that was formatted according to the
+// timestamp format. An empty timestamp returns 0.
+func unixTimestamp(value string, format string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	t, err := parseTimestamp(value, format)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(t.Unix()), nil
+}
+
+// setNewDefaultTimestampFormat sets the timestamp format of a new toggle to the timestamp format of the provider when it
+// is not configured.
+func setNewDefaultTimestampFormat(d *schema.ResourceDiff, meta *providerMeta) error {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.GetAttr("timestamp_format").IsNull() {
+		return nil
+	}
+
+	if err := d.SetNew("timestamp_format", meta.TimestampFormat); err != nil {
+		return fmt.Errorf("could not set timestamp_format: %+v", err)
+	}
+
+	return nil
+}
+
+// timestampFormatChanged returns whether the timestamp format of an existing toggle changes, which is also assumed
+// when it is unknown during plan.
+func timestampFormatChanged(d *schema.ResourceDiff) bool {
+	config := d.GetRawConfig()
+	if !config.IsNull() && !config.GetAttr("timestamp_format").IsKnown() {
+		return true
+	}
+
+	return d.HasChange("timestamp_format")
+}
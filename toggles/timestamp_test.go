@@ -0,0 +1,49 @@
+package toggles
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 123456789, time.UTC)
+
+	for format, expected := range map[string]string{
+		"":            "2021-01-01T00:00:00Z",
+		"rfc3339":     "2021-01-01T00:00:00Z",
+		"rfc3339nano": "2021-01-01T00:00:00.123456789Z",
+		"unix":        "1609459200",
+		"unix_ms":     "1609459200123",
+	} {
+		timestamp := formatTimestamp(now, format)
+		if timestamp != expected {
+			t.Fatalf("expected %s to be formatted as %s, got %s", format, expected, timestamp)
+		}
+
+		// Parsing the timestamp should return the time, up to the precision of the format.
+		parsed, err := parseTimestamp(timestamp, format)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if formatTimestamp(parsed, format) != timestamp {
+			t.Fatalf("expected %s to be parsed as %s, got %s", timestamp, now, parsed)
+		}
+	}
+}
+
+func TestReformatTimestamp(t *testing.T) {
+	timestamp, err := reformatTimestamp("2021-01-01T01:00:00.5+01:00", "rfc3339nano", "unix_ms")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if timestamp != "1609459200500" {
+		t.Fatalf("expected the timestamp to be reformatted as 1609459200500, got %s", timestamp)
+	}
+
+	// Empty timestamps, of outputs that were never set, should stay empty.
+	if timestamp, err := reformatTimestamp("", "rfc3339", "unix"); err != nil || timestamp != "" {
+		t.Fatalf("expected an empty timestamp, got %s (%v)", timestamp, err)
+	}
+}
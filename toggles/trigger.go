@@ -0,0 +1,168 @@
+package toggles
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"sort"
+	"strings"
+)
+
+const (
+	// triggerModeOnChange toggles the output when the trigger or triggers change, including changes to empty values.
+	triggerModeOnChange = "on_change"
+	// triggerModeAlways toggles the output on every apply.
+	triggerModeAlways = "always"
+	// triggerModeNever freezes the output.
+	triggerModeNever = "never"
+)
+
+// triggerModes are the valid values of the trigger_mode attribute.
+var triggerModes = []string{triggerModeOnChange, triggerModeAlways, triggerModeNever}
+
+// resourceGetter is implemented by both schema.ResourceData and schema.ResourceDiff, so the trigger logic can be
+// shared between the diff and the apply phase.
+type resourceGetter interface {
+	Get(key string) interface{}
+	GetChange(key string) (interface{}, interface{})
+	HasChange(key string) bool
+	GetRawConfig() cty.Value
+}
+
+// triggersKnown returns whether both the trigger and all values of the triggers are known during plan.
+// They are always considered known when the trigger mode does not depend on them.
+func triggersKnown(d *schema.ResourceDiff, meta *providerMeta) bool {
+	if mode := configuredTriggerMode(d, meta); mode == triggerModeAlways || mode == triggerModeNever {
+		return true
+	}
+
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		return d.NewValueKnown("trigger") && d.NewValueKnown("triggers")
+	}
+
+	return config.GetAttr("trigger").IsKnown() && config.GetAttr("triggers").IsWhollyKnown()
+}
+
+// configuredTriggerMode returns the trigger mode of a toggle, or the default trigger mode of the provider when the toggle
+// does not set one. A frozen provider never toggles. It is empty when neither sets a trigger mode.
+func configuredTriggerMode(d resourceGetter, meta *providerMeta) string {
+	if meta.Frozen {
+		return triggerModeNever
+	}
+
+	if mode := d.Get("trigger_mode").(string); mode != "" {
+		return mode
+	}
+
+	return meta.DefaultTriggerMode
+}
+
+// triggerMode returns the trigger mode of a toggle.
+// When neither the toggle nor the provider set the trigger mode, the toggle is switched on each apply if both the
+// trigger and triggers are empty, and only on changes otherwise.
+func triggerMode(d resourceGetter, meta *providerMeta) string {
+	if mode := configuredTriggerMode(d, meta); mode != "" {
+		return mode
+	}
+
+	trigger := d.Get("trigger").(string)
+	triggers := d.Get("triggers").(map[string]interface{})
+	if trigger == "" && len(triggers) == 0 {
+		return triggerModeAlways
+	}
+
+	return triggerModeOnChange
+}
+
+// shouldToggle returns whether a toggle should change its output according to its trigger mode.
+func shouldToggle(d resourceGetter, meta *providerMeta) bool {
+	return shouldToggleInMode(d, triggerMode(d, meta))
+}
+
+// shouldToggleInMode returns whether a toggle should change its output according to the given trigger mode.
+func shouldToggleInMode(d resourceGetter, mode string) bool {
+	switch mode {
+	case triggerModeAlways:
+		return true
+	case triggerModeNever:
+		return false
+	default:
+		return d.HasChange("trigger") || d.HasChange("triggers")
+	}
+}
+
+// changedTriggerKeys returns the sorted keys of the triggers that were added, removed or changed.
+func changedTriggerKeys(d resourceGetter) []interface{} {
+	rawOld, rawNew := d.GetChange("triggers")
+	old := rawOld.(map[string]interface{})
+	new := rawNew.(map[string]interface{})
+
+	var keys []string
+	for key, value := range new {
+		if oldValue, ok := old[key]; !ok || oldValue != value {
+			keys = append(keys, key)
+		}
+	}
+
+	for key := range old {
+		if _, ok := new[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	changed := make([]interface{}, len(keys), len(keys))
+	for i, key := range keys {
+		changed[i] = key
+	}
+
+	return changed
+}
+
+// setLastTriggerKeys sets last_trigger_keys to the keys of the triggers that caused the toggle using set, which is
+// either SetNew of schema.ResourceDiff or Set of schema.ResourceData.
+func setLastTriggerKeys(set func(string, interface{}) error, d resourceGetter) error {
+	if err := set("last_trigger_keys", changedTriggerKeys(d)); err != nil {
+		return fmt.Errorf("could not set last_trigger_keys: %+v", err)
+	}
+
+	return nil
+}
+
+// clearLastTriggerKeys removes last_trigger_keys from the diff, so it keeps its value unless the toggle changes.
+// The SDK stores an empty list as null, which Terraform would otherwise plan as unknown on every plan.
+func clearLastTriggerKeys(d *schema.ResourceDiff) error {
+	if err := d.Clear("last_trigger_keys"); err != nil {
+		return fmt.Errorf("could not clear last_trigger_keys: %+v", err)
+	}
+
+	return nil
+}
+
+// importTriggersPrefix starts the triggers field of an import ID, which is followed by a JSON object of the triggers.
+const importTriggersPrefix = "triggers="
+
+// parseImportTriggers parses the JSON object of the triggers at the start of value, which may contain any character,
+// and returns the triggers and the remainder of value.
+func parseImportTriggers(value string) (map[string]interface{}, string, error) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+
+	var triggers map[string]string
+	if err := decoder.Decode(&triggers); err != nil {
+		return nil, "", fmt.Errorf("invalid triggers, expected a JSON object with string values: %+v", err)
+	}
+
+	if triggers == nil {
+		return nil, "", fmt.Errorf("invalid triggers (null), expected a JSON object with string values")
+	}
+
+	result := make(map[string]interface{}, len(triggers))
+	for key, trigger := range triggers {
+		result[key] = trigger
+	}
+
+	return result, value[decoder.InputOffset():], nil
+}